@@ -0,0 +1,393 @@
+// Package analyzer checks that code follows the errx conventions
+// It reports:
+//   - exported functions returning errors built with errors.New or fmt.Errorf
+//     (without %w), which carry no errx code
+//   - Wrap calls whose error already carries an errx code, since the new code
+//     replaces it; use errx.From to add context and keep the code
+//   - Builder chains that are dropped without calling Build or Error, and
+//     builder variables that are configured but never built or returned
+//
+// Run it with go vet -vettool=$(which errxvet) or load Analyzer into
+// golangci-lint or another go/analysis driver
+package analyzer
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// errxPath is the import path of the errx package
+const errxPath = "github.com/nordew/go-errx"
+
+// Analyzer reports errors that lose or never get an errx code
+var Analyzer = &analysis.Analyzer{
+	Name:      "errx",
+	Doc:       "check that errors carry errx codes and that wrapping keeps them",
+	URL:       "https://pkg.go.dev/github.com/nordew/go-errx/analyzer",
+	Requires:  []*analysis.Analyzer{inspect.Analyzer},
+	FactTypes: []analysis.Fact{new(codedFact)},
+	Run:       run,
+}
+
+// codedFact marks functions whose non-nil errors always carry an errx code,
+// so wrapping their errors from other packages is checked too
+type codedFact struct{}
+
+func (*codedFact) AFact()         {}
+func (*codedFact) String() string { return "coded" }
+
+// wrapFuncs maps errx functions replacing the code of an error to the index
+// of their error argument; the code argument follows it
+var wrapFuncs = map[string]int{
+	"Wrap":       0,
+	"Wrapf":      0,
+	"WrapIfErr":  0,
+	"WrapIfErrf": 0,
+	"WrapSkip":   1,
+}
+
+// codedFuncs lists errx functions and methods returning a non-*Error error
+// that always carries a code
+var codedFuncs = map[string]bool{
+	"Error":      true, // Builder.Error
+	"Err":        true, // ValidationErrors.Err
+	"WrapIfErr":  true,
+	"WrapIfErrf": true,
+	"Join":       true,
+	"JoinWith":   true,
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	if pass.Pkg.Path() == errxPath {
+		return nil, nil
+	}
+
+	var funcs []*ast.FuncDecl
+	for _, file := range pass.Files {
+		if strings.HasSuffix(pass.Fset.File(file.Pos()).Name(), "_test.go") {
+			continue
+		}
+		for _, decl := range file.Decls {
+			if fn, ok := decl.(*ast.FuncDecl); ok && fn.Body != nil {
+				funcs = append(funcs, fn)
+			}
+		}
+	}
+
+	exportCodedFacts(pass, funcs)
+	for _, fn := range funcs {
+		if fn.Name.IsExported() {
+			checkBareReturns(pass, fn)
+		}
+	}
+	checkWraps(pass)
+	checkUnbuilt(pass)
+	return nil, nil
+}
+
+// exportCodedFacts marks the functions of the package whose returned errors
+// are all coded, repeating until calls between them are resolved
+func exportCodedFacts(pass *analysis.Pass, funcs []*ast.FuncDecl) {
+	for changed := true; changed; {
+		changed = false
+		for _, fn := range funcs {
+			obj, ok := pass.TypesInfo.Defs[fn.Name].(*types.Func)
+			if !ok || pass.ImportObjectFact(obj, new(codedFact)) {
+				continue
+			}
+			if returnsCoded(pass, fn) {
+				pass.ExportObjectFact(obj, new(codedFact))
+				changed = true
+			}
+		}
+	}
+}
+
+// returnsCoded reports whether every error returned by fn is nil or coded and
+// at least one isn't nil
+func returnsCoded(pass *analysis.Pass, fn *ast.FuncDecl) bool {
+	index := errorResult(pass.TypesInfo.Defs[fn.Name].(*types.Func).Signature())
+	if index < 0 {
+		return false
+	}
+
+	vars := trackCoded(pass, fn.Body)
+	coded, ok := false, true
+	eachReturn(fn.Body, func(ret *ast.ReturnStmt) {
+		if len(ret.Results) <= index {
+			ok = false // Bare return or a call returning several values
+			return
+		}
+		switch expr := ret.Results[index]; {
+		case isNil(pass, expr):
+		case isCoded(pass, vars, expr):
+			coded = true
+		default:
+			ok = false
+		}
+	})
+	return ok && coded
+}
+
+// checkBareReturns reports errors.New and fmt.Errorf errors returned by an
+// exported function
+func checkBareReturns(pass *analysis.Pass, fn *ast.FuncDecl) {
+	index := errorResult(pass.TypesInfo.Defs[fn.Name].(*types.Func).Signature())
+	if index < 0 {
+		return
+	}
+
+	eachReturn(fn.Body, func(ret *ast.ReturnStmt) {
+		if len(ret.Results) <= index {
+			return
+		}
+		call, ok := ret.Results[index].(*ast.CallExpr)
+		if !ok {
+			return
+		}
+		switch callee := typeutil.StaticCallee(pass.TypesInfo, call); {
+		case isFunc(callee, "errors", "New"):
+			pass.Reportf(call.Pos(), "exported %s returns an errors.New error without an errx code", fn.Name.Name)
+		case isFunc(callee, "fmt", "Errorf") && !wrapsError(pass, call):
+			pass.Reportf(call.Pos(), "exported %s returns a fmt.Errorf error without an errx code", fn.Name.Name)
+		}
+	})
+}
+
+// checkWraps reports Wrap calls whose error already carries a code
+func checkWraps(pass *analysis.Pass) {
+	insp := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	insp.Preorder([]ast.Node{(*ast.FuncDecl)(nil), (*ast.FuncLit)(nil)}, func(n ast.Node) {
+		var body *ast.BlockStmt
+		switch n := n.(type) {
+		case *ast.FuncDecl:
+			body = n.Body
+		case *ast.FuncLit:
+			body = n.Body
+		}
+		if body == nil || strings.HasSuffix(pass.Fset.File(n.Pos()).Name(), "_test.go") {
+			return
+		}
+
+		vars := trackCoded(pass, body)
+		ast.Inspect(body, func(n ast.Node) bool {
+			if _, ok := n.(*ast.FuncLit); ok {
+				return false
+			}
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+
+			callee := typeutil.StaticCallee(pass.TypesInfo, call)
+			if callee == nil || callee.Pkg() == nil || callee.Pkg().Path() != errxPath {
+				return true
+			}
+			index, ok := wrapFuncs[callee.Name()]
+			if !ok || len(call.Args) <= index+1 {
+				return true
+			}
+			if isGetCode(pass, call.Args[index+1]) || !isCoded(pass, vars, call.Args[index]) {
+				return true
+			}
+			pass.Reportf(call.Pos(), "errx.%s replaces the code already carried by the error; use errx.From to add context and keep it", callee.Name())
+			return true
+		})
+	})
+}
+
+// trackCoded records the assignments to variables in body, outside function
+// literals, in source order
+func trackCoded(pass *analysis.Pass, body *ast.BlockStmt) *codedVars {
+	vars := &codedVars{}
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.AssignStmt:
+			vars.assign(pass, n.Lhs, n.Rhs, n.End())
+		case *ast.ValueSpec:
+			lhs := make([]ast.Expr, len(n.Names))
+			for i, name := range n.Names {
+				lhs[i] = name
+			}
+			vars.assign(pass, lhs, n.Values, n.End())
+		}
+		return true
+	})
+	return vars
+}
+
+// codedVars records, per variable, positions where it was assigned and
+// whether the value was coded
+type codedVars struct {
+	assignments map[*types.Var][]assignment
+}
+
+type assignment struct {
+	end   token.Pos // Position after which the value is in effect
+	coded bool
+}
+
+// assign records the assignment of rhs to lhs
+func (v *codedVars) assign(pass *analysis.Pass, lhs, rhs []ast.Expr, end token.Pos) {
+	for i, expr := range lhs {
+		id, ok := expr.(*ast.Ident)
+		if !ok {
+			continue
+		}
+		obj, ok := pass.TypesInfo.ObjectOf(id).(*types.Var)
+		if !ok {
+			continue
+		}
+
+		var coded bool
+		switch {
+		case len(lhs) == len(rhs):
+			coded = isCoded(pass, v, rhs[i])
+		case len(rhs) == 1:
+			coded = codedResult(pass, rhs[0], i)
+		}
+		if v.assignments == nil {
+			v.assignments = make(map[*types.Var][]assignment)
+		}
+		v.assignments[obj] = append(v.assignments[obj], assignment{end: end, coded: coded})
+	}
+}
+
+// codedAt reports whether the variable holds a coded value at pos
+func (v *codedVars) codedAt(obj *types.Var, pos token.Pos) bool {
+	coded := false
+	for _, a := range v.assignments[obj] {
+		if a.end > pos {
+			break
+		}
+		coded = a.coded
+	}
+	return coded
+}
+
+// isCoded reports whether expr is an error known to carry an errx code
+func isCoded(pass *analysis.Pass, vars *codedVars, expr ast.Expr) bool {
+	expr = ast.Unparen(expr)
+	if isErrxError(pass.TypesInfo.TypeOf(expr)) {
+		return true
+	}
+
+	switch expr := expr.(type) {
+	case *ast.Ident:
+		obj, ok := pass.TypesInfo.ObjectOf(expr).(*types.Var)
+		return ok && vars != nil && vars.codedAt(obj, expr.Pos())
+	case *ast.CallExpr:
+		return codedResult(pass, expr, 0)
+	}
+	return false
+}
+
+// codedResult reports whether the index-th result of a call is a coded error
+func codedResult(pass *analysis.Pass, expr ast.Expr, index int) bool {
+	call, ok := ast.Unparen(expr).(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+	callee := typeutil.StaticCallee(pass.TypesInfo, call)
+	if callee == nil {
+		return false
+	}
+
+	results := callee.Signature().Results()
+	if index >= results.Len() {
+		return false
+	}
+	if isErrxError(results.At(index).Type()) {
+		return true
+	}
+	if !types.Identical(results.At(index).Type(), errorType) {
+		return false
+	}
+	if callee.Pkg() != nil && callee.Pkg().Path() == errxPath {
+		return codedFuncs[callee.Name()]
+	}
+	return pass.ImportObjectFact(callee, new(codedFact))
+}
+
+var errorType = types.Universe.Lookup("error").Type()
+
+// errorResult returns the index of the last error result of sig, or -1
+func errorResult(sig *types.Signature) int {
+	for i := sig.Results().Len() - 1; i >= 0; i-- {
+		t := sig.Results().At(i).Type()
+		if types.Identical(t, errorType) || isErrxError(t) {
+			return i
+		}
+	}
+	return -1
+}
+
+// isErrxError reports whether t is *errx.Error
+func isErrxError(t types.Type) bool {
+	return isErrxType(t, "Error")
+}
+
+// isErrxType reports whether t is a pointer to the named errx type
+func isErrxType(t types.Type, name string) bool {
+	ptr, ok := t.(*types.Pointer)
+	if !ok {
+		return false
+	}
+	named, ok := ptr.Elem().(*types.Named)
+	return ok && named.Obj().Name() == name && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == errxPath
+}
+
+// isGetCode reports whether expr is a call to errx.GetCode, which keeps the code
+func isGetCode(pass *analysis.Pass, expr ast.Expr) bool {
+	call, ok := ast.Unparen(expr).(*ast.CallExpr)
+	return ok && isFunc(typeutil.StaticCallee(pass.TypesInfo, call), errxPath, "GetCode")
+}
+
+// isFunc reports whether fn is the package-level function pkg.name
+func isFunc(fn *types.Func, pkg, name string) bool {
+	return fn != nil && fn.Pkg() != nil && fn.Pkg().Path() == pkg && fn.Name() == name &&
+		fn.Signature().Recv() == nil
+}
+
+// isNil reports whether expr is the predeclared nil
+func isNil(pass *analysis.Pass, expr ast.Expr) bool {
+	return pass.TypesInfo.Types[expr].IsNil()
+}
+
+// wrapsError reports whether a fmt.Errorf call wraps an error with %w, so the
+// result may keep the code of the wrapped error
+// Calls with a format that isn't constant are assumed to wrap
+func wrapsError(pass *analysis.Pass, call *ast.CallExpr) bool {
+	if len(call.Args) == 0 {
+		return false
+	}
+	tv := pass.TypesInfo.Types[call.Args[0]]
+	if tv.Value == nil || tv.Value.Kind() != constant.String {
+		return true
+	}
+	return strings.Contains(constant.StringVal(tv.Value), "%w")
+}
+
+// eachReturn calls fn for the return statements of body, excluding those of
+// function literals
+func eachReturn(body *ast.BlockStmt, fn func(*ast.ReturnStmt)) {
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ReturnStmt:
+			fn(n)
+		}
+		return true
+	})
+}
@@ -0,0 +1,17 @@
+package analyzer_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/nordew/go-errx/analyzer"
+)
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), analyzer.Analyzer, "a", "b")
+}
+
+func TestUnbuilt(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), analyzer.Analyzer, "builder")
+}
@@ -0,0 +1,158 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// checkUnbuilt reports Builder chains whose result is dropped without calling
+// Build or Error, and builder variables that are only ever configured
+func checkUnbuilt(pass *analysis.Pass) {
+	insp := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	// Dropped chains: errx.NewNotFound().WithMessage("...") as a statement or
+	// assigned to the blank identifier
+	insp.Preorder([]ast.Node{(*ast.ExprStmt)(nil), (*ast.AssignStmt)(nil)}, func(n ast.Node) {
+		if isTestFile(pass, n) {
+			return
+		}
+		switch n := n.(type) {
+		case *ast.ExprStmt:
+			if isBuilder(pass.TypesInfo.TypeOf(n.X)) && chainRootIsCall(pass, n.X) {
+				pass.Reportf(n.Pos(), "errx builder is never built; call Build or Error and use the result")
+			}
+		case *ast.AssignStmt:
+			for i, lhs := range n.Lhs {
+				if id, ok := lhs.(*ast.Ident); ok && id.Name == "_" && len(n.Rhs) == len(n.Lhs) &&
+					isBuilder(pass.TypesInfo.TypeOf(n.Rhs[i])) {
+					pass.Reportf(n.Rhs[i].Pos(), "errx builder is never built; call Build or Error and use the result")
+				}
+			}
+		}
+	})
+
+	// Builder variables declared in function bodies whose every use only
+	// configures them further; parameters are configured for the caller
+	locals := make(map[*types.Var]bool)
+	insp.Preorder([]ast.Node{(*ast.AssignStmt)(nil), (*ast.ValueSpec)(nil)}, func(n ast.Node) {
+		var names []ast.Expr
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			names = n.Lhs
+		case *ast.ValueSpec:
+			for _, name := range n.Names {
+				names = append(names, name)
+			}
+		}
+		for _, name := range names {
+			if id, ok := name.(*ast.Ident); ok {
+				if v, ok := pass.TypesInfo.Defs[id].(*types.Var); ok && v.Parent() != pass.Pkg.Scope() && isBuilder(v.Type()) {
+					locals[v] = true
+				}
+			}
+		}
+	})
+
+	configured := make(map[*types.Var]bool)
+	escaped := make(map[*types.Var]bool)
+	insp.WithStack([]ast.Node{(*ast.Ident)(nil)}, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if !push || isTestFile(pass, n) {
+			return true
+		}
+		v, ok := pass.TypesInfo.Uses[n.(*ast.Ident)].(*types.Var)
+		if !ok || !locals[v] {
+			return true
+		}
+		switch builderUse(pass, v, stack) {
+		case useConfigure:
+			configured[v] = true
+		case useEscape:
+			escaped[v] = true
+		}
+		return true
+	})
+	for v := range configured {
+		if !escaped[v] {
+			pass.Reportf(v.Pos(), "errx builder %s is never built or returned", v.Name())
+		}
+	}
+}
+
+type useKind int
+
+const (
+	useIgnore    useKind = iota // Assignment to the variable
+	useConfigure                // With* call whose result is dropped or stored back
+	useEscape                   // Anything else, e.g. Build, return or argument
+)
+
+// builderUse classifies the use of v at the top of stack
+func builderUse(pass *analysis.Pass, v *types.Var, stack []ast.Node) useKind {
+	i := len(stack) - 1
+	var expr ast.Expr = stack[i].(*ast.Ident)
+
+	// Climb v.WithX(...).WithY(...) chains
+	for i >= 2 {
+		sel, ok := stack[i-1].(*ast.SelectorExpr)
+		if !ok || sel.X != expr {
+			break
+		}
+		call, ok := stack[i-2].(*ast.CallExpr)
+		if !ok || call.Fun != sel || !strings.HasPrefix(sel.Sel.Name, "With") || !isBuilder(pass.TypesInfo.TypeOf(call)) {
+			break
+		}
+		expr, i = call, i-2
+	}
+	if i == 0 {
+		return useEscape
+	}
+
+	switch parent := stack[i-1].(type) {
+	case *ast.ExprStmt:
+		return useConfigure
+	case *ast.AssignStmt:
+		for j, lhs := range parent.Lhs {
+			if lhs == expr {
+				return useIgnore
+			}
+			if j < len(parent.Rhs) && parent.Rhs[j] == expr {
+				if id, ok := lhs.(*ast.Ident); ok && pass.TypesInfo.ObjectOf(id) == v {
+					return useConfigure
+				}
+			}
+		}
+	}
+	return useEscape
+}
+
+// chainRootIsCall reports whether a builder method chain starts with a call
+// creating the builder, e.g. errx.New(code) or errx.From(err), rather than
+// with a builder variable, whose uses are checked separately
+func chainRootIsCall(pass *analysis.Pass, expr ast.Expr) bool {
+	for {
+		call, ok := ast.Unparen(expr).(*ast.CallExpr)
+		if !ok {
+			return false
+		}
+		sel, ok := ast.Unparen(call.Fun).(*ast.SelectorExpr)
+		if !ok || !isBuilder(pass.TypesInfo.TypeOf(sel.X)) {
+			return true // Function or method creating the builder
+		}
+		expr = sel.X
+	}
+}
+
+// isBuilder reports whether t is *errx.Builder
+func isBuilder(t types.Type) bool {
+	return isErrxType(t, "Builder")
+}
+
+// isTestFile reports whether n is in a _test.go file
+func isTestFile(pass *analysis.Pass, n ast.Node) bool {
+	return strings.HasSuffix(pass.Fset.File(n.Pos()).Name(), "_test.go")
+}
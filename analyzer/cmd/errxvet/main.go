@@ -0,0 +1,13 @@
+// Command errxvet runs the errx analyzer, e.g. with
+//
+//	go vet -vettool=$(which errxvet) ./...
+package main
+
+import (
+	"github.com/nordew/go-errx/analyzer"
+	"golang.org/x/tools/go/analysis/singlechecker"
+)
+
+func main() {
+	singlechecker.Main(analyzer.Analyzer)
+}
@@ -0,0 +1,89 @@
+package a
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"b"
+
+	"github.com/nordew/go-errx"
+)
+
+var errPlain = errors.New("plain")
+
+func Bare() error {
+	return errors.New("bare") // want `exported Bare returns an errors.New error without an errx code`
+}
+
+func Formatted(id string) error {
+	return fmt.Errorf("user %s", id) // want `exported Formatted returns a fmt.Errorf error without an errx code`
+}
+
+func Wrapped(err error) error {
+	return fmt.Errorf("load: %w", err)
+}
+
+func unexported() error {
+	return errors.New("unexported")
+}
+
+func WrapPlain() error { // want WrapPlain:"coded"
+	return errx.Wrap(sql.ErrNoRows, errx.NotFound, "not found")
+}
+
+func WrapCoded() error { // want WrapCoded:"coded"
+	err := errx.NewNotFound().Error()
+	return errx.Wrap(err, errx.Internal, "failed") // want `errx.Wrap replaces the code already carried by the error; use errx.From to add context and keep it`
+}
+
+func WrapKeepsCode() error { // want WrapKeepsCode:"coded"
+	err := errx.NewNotFound().Error()
+	return errx.Wrap(err, errx.GetCode(err), "failed")
+}
+
+func WrapReassigned() error { // want WrapReassigned:"coded"
+	err := errx.NewNotFound().Error()
+	err = errPlain
+	return errx.Wrap(err, errx.Internal, "failed")
+}
+
+type repo struct{}
+
+func (repo) get(id string) (string, error) { // want get:"coded"
+	if id == "" {
+		return "", errx.NewNotFound().WithMessage("empty id").Error()
+	}
+	if id == "404" {
+		return "", errx.Wrap(sql.ErrNoRows, errx.NotFound, "not in database")
+	}
+	return id, nil
+}
+
+// Process mirrors ProcessUserRequest in the examples
+func Process(id string) error { // want Process:"coded"
+	if _, err := (repo{}).get(id); err != nil {
+		return errx.From(err).WithOp("Process").Error()
+	}
+	_, err := (repo{}).get(id)
+	if err != nil {
+		return errx.WrapIfErr(err, errx.Internal, "failed during user processing") // want `errx.WrapIfErr replaces the code`
+	}
+	return nil
+}
+
+func Imported(id string) error { // want Imported:"coded"
+	_, err := b.Find(id)
+	return errx.Wrapf(err, errx.Internal, "find %s", id) // want `errx.Wrapf replaces the code`
+}
+
+func Skipped() error { // want Skipped:"coded"
+	return errx.WrapSkip(1, errx.Join(errPlain), errx.Internal, "failed") // want `errx.WrapSkip replaces the code`
+}
+
+func InClosure() func() error {
+	return func() error {
+		err := errx.NewNotFound().Build()
+		return errx.Wrap(err, errx.Internal, "failed") // want `errx.Wrap replaces the code`
+	}
+}
@@ -0,0 +1,11 @@
+package b
+
+import "github.com/nordew/go-errx"
+
+// Find returns coded errors only, so callers in other packages get its fact
+func Find(id string) (string, error) { // want Find:"coded"
+	if id == "" {
+		return "", errx.NewNotFound().WithMessage("missing id").Error()
+	}
+	return id, nil
+}
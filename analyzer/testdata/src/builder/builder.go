@@ -0,0 +1,49 @@
+package builder
+
+import "github.com/nordew/go-errx"
+
+var pkgBuilder = errx.New(errx.Internal)
+
+func dropped() {
+	errx.New(errx.NotFound).WithMessage("missing") // want `errx builder is never built; call Build or Error and use the result`
+	_ = errx.NewNotFound()                         // want `errx builder is never built; call Build or Error and use the result`
+	(errx.New(errx.NotFound)).WithOp("dropped")    // want `errx builder is never built`
+}
+
+func configuredOnly() {
+	b := errx.NewNotFound() // want `errx builder b is never built or returned`
+	b.WithMessage("missing")
+	b = b.WithField("id", 1)
+}
+
+func built() error { // want built:"coded"
+	b := errx.NewNotFound()
+	b.WithMessage("missing").WithField("id", 1)
+	return b.Error()
+}
+
+func returned() *errx.Builder {
+	b := errx.NewNotFound()
+	b.WithOp("returned")
+	return b
+}
+
+func passed() {
+	b := errx.NewNotFound()
+	b.WithOp("passed")
+	use(b)
+}
+
+func use(b *errx.Builder) {
+	b.WithMessage("configured for the caller")
+}
+
+func configuredPackageBuilder() {
+	pkgBuilder.WithMessage("shared")
+}
+
+var _ = func() error {
+	var b = errx.New(errx.Internal) // want `errx builder b is never built or returned`
+	b.WithOp("literal")
+	return nil
+}
@@ -0,0 +1,7 @@
+package builder
+
+import "github.com/nordew/go-errx"
+
+func droppedInTest() {
+	errx.New(errx.NotFound).WithMessage("tests may drop builders")
+}
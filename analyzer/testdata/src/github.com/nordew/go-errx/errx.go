@@ -0,0 +1,49 @@
+// Package errx is a stub of the errx API used by the analyzer tests
+package errx
+
+type Code string
+
+const (
+	Internal Code = "INTERNAL"
+	NotFound Code = "NOT_FOUND"
+)
+
+type Error struct {
+	Code Code
+	Err  error
+}
+
+func (e *Error) Error() string { return string(e.Code) }
+
+type Builder struct{ e Error }
+
+func New(code Code) *Builder                       { return &Builder{e: Error{Code: code}} }
+func NewNotFound() *Builder                        { return New(NotFound) }
+func From(err error) *Builder                      { return &Builder{e: Error{Code: GetCode(err), Err: err}} }
+func (b *Builder) WithMessage(msg string) *Builder { return b }
+func (b *Builder) WithOp(op string) *Builder       { return b }
+func (b *Builder) WithField(k string, v any) *Builder {
+	return b
+}
+func (b *Builder) Build() *Error { return &b.e }
+func (b *Builder) Error() error  { return &b.e }
+
+func GetCode(err error) Code {
+	if e, ok := err.(*Error); ok {
+		return e.Code
+	}
+	return Internal
+}
+
+func Wrap(err error, code Code, message string) *Error              { return &Error{Code: code, Err: err} }
+func Wrapf(err error, code Code, format string, args ...any) *Error { return Wrap(err, code, format) }
+func WrapSkip(skip int, err error, code Code, message string) *Error {
+	return Wrap(err, code, message)
+}
+func WrapIfErr(err error, code Code, message string) error {
+	if err == nil {
+		return nil
+	}
+	return Wrap(err, code, message)
+}
+func Join(errs ...error) error { return &Error{Code: Internal} }
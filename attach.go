@@ -0,0 +1,70 @@
+package errx
+
+import (
+	"maps"
+	"reflect"
+)
+
+// Attach returns err carrying value as a typed payload that Detail[T]
+// retrieves, so packages can attach structured data such as a quota without
+// string keys and type assertions
+// An Error is copied with the payload added, replacing any earlier payload of
+// the same type; other errors are wrapped in a layer that keeps their text
+// and codes
+// Payloads aren't serialized
+// Returns nil for a nil error
+func Attach[T any](err error, value T) error {
+	if err == nil {
+		return nil
+	}
+	key := reflect.TypeFor[T]()
+
+	if e, ok := err.(*Error); ok {
+		attached := *e
+		attached.attachments = maps.Clone(e.attachments)
+		if attached.attachments == nil {
+			attached.attachments = make(map[reflect.Type]any, 1)
+		}
+		attached.attachments[key] = value
+		return &attached
+	}
+	return &attachment{err: err, key: key, value: value}
+}
+
+// Detail returns the payload of type T attached with Attach to the outermost
+// layer of the chain having one
+func Detail[T any](err error) (T, bool) {
+	key := reflect.TypeFor[T]()
+	for cause := range Chain(err) {
+		var value any
+		var ok bool
+		switch e := cause.(type) {
+		case *Error:
+			value, ok = e.attachments[key]
+		case *attachment:
+			value, ok = e.value, e.key == key
+		}
+		if ok {
+			// A nil interface attachment isn't a T, so only the zero value is returned
+			v, _ := value.(T)
+			return v, true
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+// attachment carries a payload for an error that isn't an Error value
+type attachment struct {
+	err   error
+	key   reflect.Type
+	value any
+}
+
+func (a *attachment) Error() string {
+	return a.err.Error()
+}
+
+func (a *attachment) Unwrap() error {
+	return a.err
+}
@@ -0,0 +1,54 @@
+package errx
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+type tenant struct{ ID string }
+
+func TestDetail(t *testing.T) {
+	base := New(NotFound).WithMessage("user not found").Error()
+
+	tests := []struct {
+		name   string
+		err    error
+		want   tenant
+		wantOK bool
+	}{
+		{"nil", nil, tenant{}, false},
+		{"none", base, tenant{}, false},
+		{"on error", Attach(base, tenant{ID: "t1"}), tenant{ID: "t1"}, true},
+		{"on plain error", Attach(errors.New("boom"), tenant{ID: "t2"}), tenant{ID: "t2"}, true},
+		{"wrapped", fmt.Errorf("handler: %w", Attach(base, tenant{ID: "t3"})), tenant{ID: "t3"}, true},
+		{"outermost wins", Attach(Wrap(Attach(base, tenant{ID: "inner"}), Internal, "outer"), tenant{ID: "outer"}), tenant{ID: "outer"}, true},
+		{"inner", Wrap(Attach(base, tenant{ID: "inner"}), Internal, "outer"), tenant{ID: "inner"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Detail[tenant](tt.err)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("Detail = %v, %t, want %v, %t", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestDetailNilInterface(t *testing.T) {
+	for _, err := range []error{New(Internal).Error(), errors.New("boom")} {
+		got, ok := Detail[error](Attach[error](err, nil))
+		if got != nil || !ok {
+			t.Errorf("Detail[error] = %v, %t, want nil, true", got, ok)
+		}
+	}
+}
+
+func TestAttachDoesNotModify(t *testing.T) {
+	base := New(NotFound).Error()
+	_ = Attach(base, tenant{ID: "t1"})
+	if _, ok := Detail[tenant](base); ok {
+		t.Error("Attach modified the original error")
+	}
+}
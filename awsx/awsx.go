@@ -0,0 +1,162 @@
+// Package awsx maps AWS SDK v2 and smithy API errors to errx errors
+package awsx
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/aws/smithy-go"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+	"github.com/nordew/go-errx"
+)
+
+// Field keys used for the details of an AWS error
+const (
+	ErrorCodeField = "aws_error_code"
+	RequestIDField = "aws_request_id"
+)
+
+// DefaultThrottleDelay is the retry delay attached to throttling errors whose
+// response has no Retry-After header
+const DefaultThrottleDelay = time.Second
+
+// codes maps AWS error codes to errx codes
+var codes = map[string]errx.Code{
+	"NotFound":                  errx.NotFound,
+	"NoSuchKey":                 errx.NotFound,
+	"NoSuchBucket":              errx.NotFound,
+	"NoSuchEntity":              errx.NotFound,
+	"NoSuchUpload":              errx.NotFound,
+	"ResourceNotFoundException": errx.NotFound,
+
+	"Throttling":                             errx.TooManyRequests,
+	"ThrottlingException":                    errx.TooManyRequests,
+	"ThrottledException":                     errx.TooManyRequests,
+	"TooManyRequestsException":               errx.TooManyRequests,
+	"RequestLimitExceeded":                   errx.TooManyRequests,
+	"RequestThrottled":                       errx.TooManyRequests,
+	"RequestThrottledException":              errx.TooManyRequests,
+	"ProvisionedThroughputExceededException": errx.TooManyRequests,
+	"SlowDown":                               errx.TooManyRequests,
+
+	"AccessDenied":          errx.Forbidden,
+	"AccessDeniedException": errx.Forbidden,
+	"UnauthorizedOperation": errx.Forbidden,
+	"Forbidden":             errx.Forbidden,
+
+	"ExpiredToken":                        errx.Unauthorized,
+	"ExpiredTokenException":               errx.Unauthorized,
+	"InvalidAccessKeyId":                  errx.Unauthorized,
+	"InvalidClientTokenId":                errx.Unauthorized,
+	"SignatureDoesNotMatch":               errx.Unauthorized,
+	"UnrecognizedClientException":         errx.Unauthorized,
+	"AuthFailure":                         errx.Unauthorized,
+	"InvalidSignatureException":           errx.Unauthorized,
+	"MissingAuthenticationTokenException": errx.Unauthorized,
+
+	"RequestTimeout":          errx.Timeout,
+	"RequestTimeoutException": errx.Timeout,
+
+	"ValidationError":           errx.Validation,
+	"ValidationException":       errx.Validation,
+	"InvalidParameterValue":     errx.Validation,
+	"InvalidParameterException": errx.Validation,
+	"InvalidRequest":            errx.BadRequest,
+
+	"BucketAlreadyExists":            errx.AlreadyExists,
+	"BucketAlreadyOwnedByYou":        errx.AlreadyExists,
+	"EntityAlreadyExists":            errx.AlreadyExists,
+	"ResourceAlreadyExistsException": errx.AlreadyExists,
+	"AlreadyExistsException":         errx.AlreadyExists,
+
+	"ConflictException":            errx.Conflict,
+	"ResourceInUseException":       errx.Conflict,
+	"TransactionConflictException": errx.Conflict,
+
+	"ConditionalCheckFailedException": errx.PreconditionFailed,
+	"PreconditionFailed":              errx.PreconditionFailed,
+
+	"LimitExceededException":        errx.ResourceExhausted,
+	"ServiceQuotaExceededException": errx.ResourceExhausted,
+
+	"ServiceUnavailable":          errx.Unavailable,
+	"ServiceUnavailableException": errx.Unavailable,
+	"InternalFailure":             errx.Internal,
+	"InternalServerError":         errx.Internal,
+	"NotImplemented":              errx.NotImplemented,
+
+	"EntityTooLarge":                 errx.PayloadTooLarge,
+	"RequestEntityTooLargeException": errx.PayloadTooLarge,
+	"UnsupportedMediaTypeException":  errx.UnsupportedMediaType,
+}
+
+// MapError wraps an AWS error in an Error with a code derived from its AWS
+// error code, recording the AWS error code and request ID as fields
+// Unknown error codes are mapped from the HTTP status, then the fault
+// Throttling errors carry the Retry-After delay of the response, or
+// DefaultThrottleDelay if it has none
+// Errors that already carry an errx code are returned unchanged
+// Returns nil for a nil error
+func MapError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var e *errx.Error
+	if errors.As(err, &e) {
+		return err
+	}
+
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) {
+		return errx.WrapSkip(1, err, errx.Internal, "")
+	}
+
+	code := Code(err)
+	b := errx.New(code).
+		WithCause(err).
+		WithField(ErrorCodeField, apiErr.ErrorCode()).
+		WithCallerSkip(1)
+	var reqErr interface{ ServiceRequestID() string }
+	if errors.As(err, &reqErr) && reqErr.ServiceRequestID() != "" {
+		b.WithField(RequestIDField, reqErr.ServiceRequestID())
+	}
+	if code == errx.TooManyRequests {
+		b.WithRetryAfter(retryAfter(err))
+	}
+	return b.Build()
+}
+
+// Code returns the errx code describing an AWS error
+func Code(err error) errx.Code {
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) {
+		return errx.Internal
+	}
+	if code, ok := codes[apiErr.ErrorCode()]; ok {
+		return code
+	}
+
+	var respErr *smithyhttp.ResponseError
+	if errors.As(err, &respErr) && respErr.Response != nil {
+		if code := errx.CodeFromHTTPStatus(respErr.HTTPStatusCode()); code != "" {
+			return code
+		}
+	}
+	if apiErr.ErrorFault() == smithy.FaultClient {
+		return errx.BadRequest
+	}
+	return errx.Internal
+}
+
+// retryAfter returns the delay from the Retry-After header of the response
+func retryAfter(err error) time.Duration {
+	var respErr *smithyhttp.ResponseError
+	if errors.As(err, &respErr) && respErr.Response != nil {
+		if secs, convErr := strconv.Atoi(respErr.Response.Header.Get("Retry-After")); convErr == nil && secs > 0 {
+			return time.Duration(secs) * time.Second
+		}
+	}
+	return DefaultThrottleDelay
+}
@@ -0,0 +1,10 @@
+module github.com/nordew/go-errx/awsx
+
+go 1.24.1
+
+require (
+	github.com/aws/smithy-go v1.22.4
+	github.com/nordew/go-errx v0.0.0
+)
+
+replace github.com/nordew/go-errx => ../
@@ -0,0 +1,175 @@
+package errx
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// Batch collects the errors of the items of a bulk operation, such as an
+// import, so a partial failure can be reported item by item
+// It's safe for concurrent use
+//
+//	batch := errx.NewBatch(len(rows))
+//	for i, row := range rows {
+//		batch.Add(i, insert(ctx, row))
+//	}
+//	return batch.Err()
+type Batch struct {
+	mu    sync.Mutex
+	total int
+	items []BatchItem
+}
+
+// BatchItem is the error of one item of a batch, identified by its index or
+// key
+type BatchItem struct {
+	Index int    // Position of the item, -1 if identified by key
+	Key   string // Identifier of the item, if added with AddKey
+	Err   error
+}
+
+// NewBatch creates a Batch for the given number of items, or an unknown
+// number if total is 0
+func NewBatch(total int) *Batch {
+	return &Batch{total: total}
+}
+
+// Add records the error of the item at index; nil errors are ignored, so
+// the result of each item can be added unconditionally
+func (b *Batch) Add(index int, err error) {
+	b.add(BatchItem{Index: index, Err: err})
+}
+
+// AddKey records the error of the item identified by key; nil errors are
+// ignored
+func (b *Batch) AddKey(key string, err error) {
+	b.add(BatchItem{Index: -1, Key: key, Err: err})
+}
+
+func (b *Batch) add(item BatchItem) {
+	if item.Err == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.items = append(b.items, item)
+}
+
+// Len returns the number of failed items
+func (b *Batch) Len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.items)
+}
+
+// Err returns a BatchError holding the failed items in the order they were
+// added, or nil if none failed
+// Its code is chosen from the item errors by the strategy set with
+// SetJoinStrategy, as Join does
+func (b *Batch) Err() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if len(b.items) == 0 {
+		return nil
+	}
+
+	items := slices.Clone(b.items)
+	errs := make([]error, len(items))
+	for i, item := range items {
+		errs[i] = item.Err
+	}
+	strategy := JoinStrategy(MostSevere)
+	if s := joinStrategy.Load(); s != nil {
+		strategy = *s
+	}
+	return &BatchError{items: items, errs: errs, total: b.total, code: strategy(errs)}
+}
+
+// BatchError is a partial failure of a bulk operation, created by Batch.Err
+// errors.Is, errors.As and IsCode match each item error, while GetCode and
+// errors.As with an *Error target see the overall code
+type BatchError struct {
+	items []BatchItem
+	errs  []error
+	total int
+	code  Code
+}
+
+// Error implements the error interface, e.g. "2 of 10 items failed"
+func (b *BatchError) Error() string {
+	if b.total > 0 {
+		return fmt.Sprintf("%d of %d items failed", len(b.items), b.total)
+	}
+	if len(b.items) == 1 {
+		return "1 item failed"
+	}
+	return fmt.Sprintf("%d items failed", len(b.items))
+}
+
+// Unwrap returns the item errors for errors.Is and errors.As
+func (b *BatchError) Unwrap() []error {
+	return b.errs
+}
+
+// Items returns the failed items
+func (b *BatchError) Items() []BatchItem {
+	return b.items
+}
+
+// Total returns the number of items of the batch, 0 if unknown
+func (b *BatchError) Total() int {
+	return b.total
+}
+
+// Code returns the overall code
+func (b *BatchError) Code() Code {
+	return b.code
+}
+
+// As allows errors.As to treat the BatchError as an Error with the overall
+// code and its text as message
+func (b *BatchError) As(target any) bool {
+	t, ok := target.(**Error)
+	if !ok {
+		return false
+	}
+	*t = &Error{Code: b.code, Message: b.Error()}
+	return true
+}
+
+// jsonBatchItem is the JSON representation of a failed item
+type jsonBatchItem struct {
+	Index *int       `json:"index,omitempty"`
+	Key   string     `json:"key,omitempty"`
+	Error *jsonError `json:"error"`
+}
+
+// MarshalJSON implements json.Marshaler, serializing the overall code and
+// the error of every failed item
+func (b *BatchError) MarshalJSON() ([]byte, error) {
+	items := make([]jsonBatchItem, len(b.items))
+	for i, item := range b.items {
+		items[i] = jsonBatchItem{Key: item.Key, Error: toJSONError(item.Err)}
+		if item.Index >= 0 {
+			items[i].Index = &item.Index
+		}
+	}
+
+	return json.Marshal(struct {
+		Code        Code            `json:"code"`
+		NumericCode int             `json:"numeric_code"`
+		Message     string          `json:"message"`
+		Total       int             `json:"total,omitempty"`
+		Failed      int             `json:"failed"`
+		Items       []jsonBatchItem `json:"items"`
+	}{
+		Code:        b.code,
+		NumericCode: CodeNumeric(b.code),
+		Message:     b.Error(),
+		Total:       b.total,
+		Failed:      len(b.items),
+		Items:       items,
+	})
+}
@@ -0,0 +1,73 @@
+package errx
+
+import "net/http"
+
+// Category is a coarse-grained class of codes; a code can belong to several
+type Category uint8
+
+// Code categories
+const (
+	CategoryClient    Category = 1 << iota // Caused by the request, e.g. invalid input
+	CategoryServer                         // Caused by the server or its dependencies
+	CategoryTransient                      // Likely to succeed if tried again later
+	CategorySecurity                       // Related to authentication or authorization
+)
+
+// Has reports whether c includes every category in other
+func (c Category) Has(other Category) bool {
+	return c&other == other
+}
+
+// CodeCategories returns the categories of the given code
+// Codes registered without categories are classified from their HTTP status,
+// retryability and whether the status is 401 or 403
+func CodeCategories(code Code) Category {
+	info, ok := LookupCode(code)
+	if ok && info.Categories != 0 {
+		return info.Categories
+	}
+
+	status := CodeHTTPStatus(code)
+	var c Category
+	if status >= 500 {
+		c |= CategoryServer
+	} else if status >= 400 {
+		c |= CategoryClient
+	}
+	if info.Retryable {
+		c |= CategoryTransient
+	}
+	if status == http.StatusUnauthorized || status == http.StatusForbidden {
+		c |= CategorySecurity
+	}
+	return c
+}
+
+// HasCategory reports whether the error's code belongs to the given category
+func HasCategory(err error, c Category) bool {
+	if err == nil {
+		return false
+	}
+	return CodeCategories(GetCode(err)).Has(c)
+}
+
+// IsClientError reports whether the error was caused by the request
+func IsClientError(err error) bool {
+	return HasCategory(err, CategoryClient)
+}
+
+// IsServerError reports whether the error was caused by the server or its dependencies
+// Errors that aren't Error values count as server errors
+func IsServerError(err error) bool {
+	return HasCategory(err, CategoryServer)
+}
+
+// IsTransient reports whether the error is likely to go away if tried again later
+func IsTransient(err error) bool {
+	return HasCategory(err, CategoryTransient)
+}
+
+// IsSecurityError reports whether the error relates to authentication or authorization
+func IsSecurityError(err error) bool {
+	return HasCategory(err, CategorySecurity)
+}
@@ -0,0 +1,124 @@
+package errx
+
+import (
+	"errors"
+	"iter"
+	"slices"
+	"strings"
+)
+
+// Chain returns an iterator over err and every error it wraps, depth first:
+// each error is followed by its cause, and each member of a joined error
+// (errors.Join, Join or any Unwrap() []error) by its own causes before the
+// next member
+// A nil error yields nothing
+func Chain(err error) iter.Seq[error] {
+	return func(yield func(error) bool) {
+		walk(err, yield)
+	}
+}
+
+// ChainErrx returns an iterator over the Error values of the chain, in the
+// order of Chain
+func ChainErrx(err error) iter.Seq[*Error] {
+	return func(yield func(*Error) bool) {
+		for cause := range Chain(err) {
+			if e, ok := cause.(*Error); ok && !yield(e) {
+				return
+			}
+		}
+	}
+}
+
+// Codes returns every code found in the chain, in the order of Chain, so
+// the original classification of a re-wrapped error stays available
+// Codes of Error, RemoteError, MultiError and BatchError values and Code
+// sentinels are collected, each listed once at its outermost position
+// Returns nil if the chain has no codes
+func Codes(err error) []Code {
+	var codes []Code
+	for cause := range Chain(err) {
+		var code Code
+		switch e := cause.(type) {
+		case *Error:
+			code = e.Code
+		case *RemoteError:
+			code = e.Code
+		case *MultiError:
+			code = e.code
+		case *BatchError:
+			code = e.code
+		case Code:
+			code = e
+		}
+		if code != "" && !slices.Contains(codes, code) {
+			codes = append(codes, code)
+		}
+	}
+	return codes
+}
+
+// Messages returns the message of each layer of the chain, outermost first,
+// for building a breadcrumb trail such as
+// strings.Join(errx.Messages(err), ": ")
+// Layers that aren't Error values contribute their text without the text of
+// their cause, as do Errors created with Errorf and %w; joined errors
+// contribute only their members and empty messages are left out
+func Messages(err error) []string {
+	var messages []string
+	for cause := range Chain(err) {
+		var message string
+		switch e := cause.(type) {
+		case *Error:
+			message = e.Message
+			if e.inlineCause && e.Err != nil {
+				message = trimCause(message, e.Err)
+			}
+		case interface{ Unwrap() []error }:
+			continue
+		default:
+			message = cause.Error()
+			if inner := errors.Unwrap(cause); inner != nil {
+				message = trimCause(message, inner)
+			}
+		}
+		if message != "" {
+			messages = append(messages, message)
+		}
+	}
+	return messages
+}
+
+// trimCause removes the text of cause from the end of a message that was
+// formatted to include it, along with the separator before it
+func trimCause(message string, cause error) string {
+	trimmed, ok := strings.CutSuffix(message, cause.Error())
+	if !ok {
+		return message
+	}
+	trimmed = strings.TrimSuffix(trimmed, ": ")
+	return strings.TrimSuffix(trimmed, ":")
+}
+
+// walk yields err and its causes depth first, returning false once yield does
+func walk(err error, yield func(error) bool) bool {
+	for err != nil {
+		if !yield(err) {
+			return false
+		}
+		switch u := err.(type) {
+		case interface{ Unwrap() []error }:
+			for _, member := range u.Unwrap() {
+				if !walk(member, yield) {
+					return false
+				}
+			}
+			return true
+		case interface{ Unwrap() error }:
+			err = u.Unwrap()
+		default:
+			return true
+		}
+	}
+	return true
+}
@@ -0,0 +1,59 @@
+package errx
+
+import (
+	"errors"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Challenge is an HTTP authentication challenge, sent in the
+// WWW-Authenticate header of Unauthorized responses (RFC 9110)
+type Challenge struct {
+	Scheme string            // Authentication scheme, e.g. "Bearer"
+	Params map[string]string // Auth parameters, e.g. realm or error
+}
+
+// String formats the challenge as a WWW-Authenticate value, e.g.
+// Bearer realm="api", error="invalid_token"
+// The realm comes first and the other parameters follow in key order
+func (c Challenge) String() string {
+	if len(c.Params) == 0 {
+		return c.Scheme
+	}
+
+	keys := slices.Sorted(maps.Keys(c.Params))
+	if i := slices.Index(keys, "realm"); i > 0 {
+		keys = slices.Insert(slices.Delete(keys, i, i+1), 0, "realm")
+	}
+	params := make([]string, len(keys))
+	for i, key := range keys {
+		params[i] = key + "=" + quote(c.Params[key])
+	}
+	return c.Scheme + " " + strings.Join(params, ", ")
+}
+
+// quote formats s as an HTTP quoted-string
+func quote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// WithChallenge adds an authentication challenge telling the client how to
+// authenticate, e.g. WithChallenge("Bearer", map[string]string{"realm": "api"})
+// httpx sends each challenge as a WWW-Authenticate header, as RFC 9110
+// requires for 401 responses
+func (b *Builder) WithChallenge(scheme string, params map[string]string) *Builder {
+	b.challenges = append(b.challenges, Challenge{Scheme: scheme, Params: maps.Clone(params)})
+	return b
+}
+
+// Challenges returns the authentication challenges of the first Error in the
+// chain having any
+func Challenges(err error) []Challenge {
+	for cause := err; cause != nil; cause = errors.Unwrap(cause) {
+		if e, ok := cause.(*Error); ok && len(e.challenges) > 0 {
+			return e.challenges
+		}
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+// Package chix renders errx errors with go-chi/render
+package chix
+
+import (
+	"net/http"
+
+	"github.com/go-chi/render"
+	"github.com/nordew/go-errx"
+	"github.com/nordew/go-errx/httpx"
+)
+
+// ErrResponse is a render.Renderer for an error
+// It renders the same body and headers as httpx.WriteError with the status
+// mapped from the error's code
+type ErrResponse struct {
+	httpx.Response
+	err error
+}
+
+// NewErrResponse returns the renderer for err
+func NewErrResponse(err error) *ErrResponse {
+	return &ErrResponse{
+		Response: httpx.NewResponse(err),
+		err:      err,
+	}
+}
+
+// Render implements render.Renderer
+// It sets the headers of the error and localizes the message from the
+// request's Accept-Language header, as httpx.WriteError does
+func (e *ErrResponse) Render(w http.ResponseWriter, r *http.Request) error {
+	e.Response = httpx.Prepare(w.Header(), r.Header.Get("Accept-Language"), e.err)
+	render.Status(r, errx.HTTPStatus(e.err))
+	return nil
+}
+
+// Respond renders err as the response
+func Respond(w http.ResponseWriter, r *http.Request, err error) {
+	render.Render(w, r, NewErrResponse(err))
+}
+
+// Handle adapts a handler returning an error into an http.HandlerFunc that
+// renders the error with Respond
+func Handle(fn func(w http.ResponseWriter, r *http.Request) error) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := fn(w, r); err != nil {
+			Respond(w, r, err)
+		}
+	}
+}
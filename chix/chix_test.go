@@ -0,0 +1,130 @@
+package chix
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nordew/go-errx"
+	"github.com/nordew/go-errx/httpx"
+)
+
+// germanLocalizer translates every message to a fixed German text
+type germanLocalizer struct{}
+
+func (germanLocalizer) LocalizeAccept(err error, acceptLanguage string) (string, string) {
+	if acceptLanguage == "de" {
+		return "auf Deutsch", "de"
+	}
+	return errx.GetMessage(err), ""
+}
+
+// comparedHeaders are the headers both responses must agree on
+var comparedHeaders = []string{
+	errx.CodeHeader,
+	errx.IDHeader,
+	"Retry-After",
+	"X-RateLimit-Limit",
+	"X-RateLimit-Remaining",
+	"X-RateLimit-Reset",
+	"WWW-Authenticate",
+	"X-Content-Type-Options",
+	"Content-Language",
+	"Vary",
+}
+
+func TestRespondMatchesWriteError(t *testing.T) {
+	httpx.SetLocalizer(germanLocalizer{})
+	defer httpx.SetLocalizer(nil)
+
+	reset := time.Now().Add(time.Minute).Truncate(time.Second)
+	tests := []struct {
+		name   string
+		err    error
+		accept string
+	}{
+		{"not found", errx.New(errx.NotFound).WithMessage("user not found").WithID("id-1").WithField("user_id", 7).Error(), ""},
+		{"localized", errx.New(errx.Validation).WithMessage("invalid email").Error(), "de"},
+		{"rate limited", errx.NewRateLimited(100, 0, reset).WithMessage("slow down").Error(), ""},
+		{"challenge", errx.New(errx.Unauthorized).WithChallenge("Bearer", map[string]string{"realm": "api"}).Error(), ""},
+		{"unavailable", errx.New(errx.Unavailable).WithMessage("pool exhausted: db-7").WithRetryAfter(3 * time.Second).Error(), ""},
+		{"panic", errx.FromPanic("secret"), ""},
+		{"plain error", errors.New("dial tcp 10.0.0.5: refused"), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept-Language", tt.accept)
+			}
+
+			got := httptest.NewRecorder()
+			Handle(func(w http.ResponseWriter, r *http.Request) error { return tt.err })(got, req)
+			want := httptest.NewRecorder()
+			httpx.WriteError(want, req, tt.err)
+
+			if got.Code != want.Code {
+				t.Errorf("status = %d, want %d", got.Code, want.Code)
+			}
+			for _, key := range comparedHeaders {
+				if g, w := got.Header().Values(key), want.Header().Values(key); !slices.Equal(g, w) {
+					t.Errorf("header %s = %q, want %q", key, g, w)
+				}
+			}
+			if ct := got.Header().Get("Content-Type"); !strings.HasPrefix(ct, "application/json") {
+				t.Errorf("Content-Type = %q, want JSON", ct)
+			}
+			if g, w := decode(t, got.Body.Bytes()), decode(t, want.Body.Bytes()); !jsonEqual(g, w) {
+				t.Errorf("body = %s, want %s", got.Body.Bytes(), want.Body.Bytes())
+			}
+		})
+	}
+}
+
+func TestRespondMasksServerErrors(t *testing.T) {
+	rec := httptest.NewRecorder()
+	Respond(rec, httptest.NewRequest(http.MethodGet, "/", nil),
+		errx.New(errx.Internal).WithMessage("query failed: password=hunter2").WithField("dsn", "db-7").Error())
+
+	var got httpx.Response
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Code != http.StatusInternalServerError || got.Message != http.StatusText(http.StatusInternalServerError) ||
+		len(got.Fields) != 0 {
+		t.Errorf("response = %d %s, want a masked 500", rec.Code, rec.Body.Bytes())
+	}
+}
+
+func TestHandleWithoutError(t *testing.T) {
+	rec := httptest.NewRecorder()
+	Handle(func(w http.ResponseWriter, r *http.Request) error {
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	})(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if rec.Code != http.StatusNoContent || rec.Body.Len() != 0 {
+		t.Errorf("response = %d %s, want the handler's 204", rec.Code, rec.Body.Bytes())
+	}
+}
+
+func decode(t *testing.T, data []byte) any {
+	t.Helper()
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		t.Fatalf("decoding %s: %v", data, err)
+	}
+	return v
+}
+
+func jsonEqual(a, b any) bool {
+	x, _ := json.Marshal(a)
+	y, _ := json.Marshal(b)
+	return string(x) == string(y)
+}
@@ -0,0 +1,12 @@
+module github.com/nordew/go-errx/chix
+
+go 1.24.1
+
+require (
+	github.com/go-chi/render v1.0.3
+	github.com/nordew/go-errx v0.0.0
+)
+
+require github.com/ajg/form v1.5.1 // indirect
+
+replace github.com/nordew/go-errx => ../
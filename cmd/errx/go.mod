@@ -0,0 +1,13 @@
+module github.com/nordew/go-errx/cmd/errx
+
+go 1.24.1
+
+require github.com/nordew/go-errx v0.0.0
+
+require (
+	golang.org/x/mod v0.26.0 // indirect
+	golang.org/x/sync v0.16.0 // indirect
+	golang.org/x/tools v0.35.0
+)
+
+replace github.com/nordew/go-errx => ../../
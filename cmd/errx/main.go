@@ -0,0 +1,71 @@
+// Command errx provides tooling for codebases using errx
+//
+// Usage:
+//
+//	errx report [-format text|json|markdown] [-tests] [packages]
+//
+// The report subcommand statically scans the packages (./... by default) and
+// lists the codes used and where errors with them are constructed, custom
+// codes that are never registered with errx.RegisterCode and registered
+// custom codes that are never used
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	var err error
+	switch cmd, args := os.Args[1], os.Args[2:]; cmd {
+	case "report":
+		err = runReport(args)
+	case "help", "-h", "-help", "--help":
+		usage()
+		return
+	default:
+		fmt.Fprintf(os.Stderr, "errx: unknown command %q\n", cmd)
+		usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "errx:", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, `Usage: errx <command> [arguments]
+
+Commands:
+  report    list codes, construction sites, unregistered and unused codes`)
+}
+
+// runReport implements the report subcommand
+func runReport(args []string) error {
+	flags := flag.NewFlagSet("report", flag.ExitOnError)
+	format := flags.String("format", "text", "output format: text, json or markdown")
+	tests := flags.Bool("tests", false, "include test files")
+	flags.Parse(args)
+
+	write, ok := formats[*format]
+	if !ok {
+		return fmt.Errorf("unknown format %q", *format)
+	}
+
+	patterns := flags.Args()
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+	report, err := scan(patterns, *tests)
+	if err != nil {
+		return err
+	}
+	return write(os.Stdout, report)
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/nordew/go-errx"
+)
+
+// formats maps the -format values to report writers
+var formats = map[string]func(io.Writer, *Report) error{
+	"text":     writeText,
+	"json":     writeJSON,
+	"markdown": writeMarkdown,
+}
+
+// writeText writes a summary table followed by the construction sites of each code
+func writeText(w io.Writer, r *Report) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "CODE\tCUSTOM\tREGISTERED\tSITES")
+	for _, usage := range r.Codes {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\n", usage.Code, yesNo(usage.Custom), yesNo(usage.Registered), len(usage.Sites))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	for _, usage := range r.Codes {
+		if len(usage.Sites) == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "\n%s:\n", usage.Code)
+		for _, site := range usage.Sites {
+			fmt.Fprintf(w, "  %s\t%s\n", site.Position, site.Call)
+		}
+	}
+
+	if len(r.Unregistered) > 0 {
+		fmt.Fprintf(w, "\nUnregistered codes: %s\n", joinCodes(r.Unregistered, ", "))
+	}
+	if len(r.Unused) > 0 {
+		fmt.Fprintf(w, "\nUnused registered codes: %s\n", joinCodes(r.Unused, ", "))
+	}
+	return nil
+}
+
+// writeJSON writes the report as indented JSON
+func writeJSON(w io.Writer, r *Report) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// writeMarkdown writes the report as a Markdown document for docs
+func writeMarkdown(w io.Writer, r *Report) error {
+	fmt.Fprintln(w, "# Error Codes")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "| Code | Custom | Registered | Sites |")
+	fmt.Fprintln(w, "|------|--------|------------|-------|")
+	for _, usage := range r.Codes {
+		fmt.Fprintf(w, "| `%s` | %s | %s | %d |\n", usage.Code, yesNo(usage.Custom), yesNo(usage.Registered), len(usage.Sites))
+	}
+
+	if len(r.Unregistered) > 0 {
+		fmt.Fprintf(w, "\n## Unregistered Codes\n\n%s\n", bulletCodes(r.Unregistered))
+	}
+	if len(r.Unused) > 0 {
+		fmt.Fprintf(w, "\n## Unused Registered Codes\n\n%s\n", bulletCodes(r.Unused))
+	}
+
+	fmt.Fprintln(w, "\n## Construction Sites")
+	for _, usage := range r.Codes {
+		if len(usage.Sites) == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "\n### `%s`\n\n", usage.Code)
+		for _, site := range usage.Sites {
+			fmt.Fprintf(w, "- `%s` (`%s`)\n", site.Position, site.Call)
+		}
+	}
+	return nil
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
+
+// joinCodes joins codes with a separator
+func joinCodes(codes []errx.Code, sep string) string {
+	s := make([]string, len(codes))
+	for i, code := range codes {
+		s[i] = string(code)
+	}
+	return strings.Join(s, sep)
+}
+
+// bulletCodes formats codes as a Markdown list
+func bulletCodes(codes []errx.Code) string {
+	return "- `" + joinCodes(codes, "`\n- `") + "`"
+}
@@ -0,0 +1,248 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/nordew/go-errx"
+	"golang.org/x/tools/go/packages"
+)
+
+// errxPath is the import path of the errx package
+const errxPath = "github.com/nordew/go-errx"
+
+// Report is the inventory of codes in the scanned packages
+type Report struct {
+	Codes        []CodeUsage `json:"codes"`        // Every known code, sorted
+	Unregistered []errx.Code `json:"unregistered"` // Custom codes never registered
+	Unused       []errx.Code `json:"unused"`       // Registered custom codes never constructed
+}
+
+// CodeUsage describes a code and where errors with it are constructed
+type CodeUsage struct {
+	Code         errx.Code `json:"code"`
+	Custom       bool      `json:"custom"`                  // Declared outside errx
+	Registered   bool      `json:"registered"`              // Standard or registered with errx.RegisterCode
+	Declared     string    `json:"declared,omitempty"`      // Position of the constant declaring the code
+	RegisteredAt string    `json:"registered_at,omitempty"` // Position of the RegisterCode call
+	Sites        []Site    `json:"sites,omitempty"`
+}
+
+// Site is a place where an error with a code is constructed
+type Site struct {
+	Position string `json:"position"` // file:line:column, relative to the working directory
+	Call     string `json:"call"`     // errx function or method called, e.g. "errx.Wrap"
+}
+
+// constructors lists the errx functions and methods taking the code of the
+// error they create
+var constructors = map[string]bool{
+	"New":        true,
+	"Errorf":     true,
+	"Wrap":       true,
+	"Wrapf":      true,
+	"WrapSkip":   true,
+	"WrapIfErr":  true,
+	"WrapIfErrf": true,
+	"DeferWrap":  true,
+	"DeferWrapf": true,
+	"WithCode":   true,
+}
+
+// scan loads the packages matching patterns and builds the report
+func scan(patterns []string, tests bool) (*Report, error) {
+	cfg := &packages.Config{
+		Mode:  packages.NeedName | packages.NeedFiles | packages.NeedImports | packages.NeedDeps | packages.NeedSyntax | packages.NeedTypes | packages.NeedTypesInfo,
+		Tests: tests,
+	}
+	pkgs, err := packages.Load(cfg, patterns...)
+	if err != nil {
+		return nil, err
+	}
+	if packages.PrintErrors(pkgs) > 0 {
+		return nil, fmt.Errorf("packages contain errors")
+	}
+
+	s := &scanner{codes: make(map[errx.Code]*CodeUsage), seen: make(map[token.Position]bool)}
+	for _, info := range errx.RegisteredCodes() {
+		s.code(info.Code).Registered = true
+	}
+	for _, pkg := range pkgs {
+		if pkg.PkgPath == errxPath {
+			continue
+		}
+		s.scanPackage(pkg)
+	}
+	return s.report(), nil
+}
+
+type scanner struct {
+	codes map[errx.Code]*CodeUsage
+	seen  map[token.Position]bool // Sites already recorded, as test variants repeat files
+}
+
+// code returns the usage of a code, adding it if needed
+func (s *scanner) code(code errx.Code) *CodeUsage {
+	usage, ok := s.codes[code]
+	if !ok {
+		usage = &CodeUsage{Code: code}
+		s.codes[code] = usage
+	}
+	return usage
+}
+
+// scanPackage records the code declarations, registrations and construction
+// sites of a package
+func (s *scanner) scanPackage(pkg *packages.Package) {
+	for id, obj := range pkg.TypesInfo.Defs {
+		c, ok := obj.(*types.Const)
+		if !ok || !isCode(c.Type()) || c.Val().Kind() != constant.String {
+			continue
+		}
+		usage := s.code(errx.Code(constant.StringVal(c.Val())))
+		usage.Custom = true
+		usage.Declared = position(pkg.Fset, id.Pos())
+	}
+	for _, file := range pkg.Syntax {
+		s.scanFile(pkg, file)
+	}
+}
+
+// scanFile records the registrations and construction sites of a file
+func (s *scanner) scanFile(pkg *packages.Package, file *ast.File) {
+	ast.Inspect(file, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		fn, id := callee(pkg.TypesInfo, call)
+		if fn == nil || fn.Pkg() == nil || fn.Pkg().Path() != errxPath {
+			return true
+		}
+
+		pos := pkg.Fset.Position(id.Pos())
+		if s.seen[pos] {
+			return true
+		}
+
+		switch code, ok := constructedCode(pkg.TypesInfo, fn, call); {
+		case fn.Name() == "RegisterCode" && len(call.Args) > 0:
+			if code, ok := constantCode(pkg.TypesInfo, call.Args[0]); ok {
+				s.seen[pos] = true
+				usage := s.code(code)
+				usage.Registered = true
+				usage.RegisteredAt = position(pkg.Fset, id.Pos())
+			}
+		case ok:
+			s.seen[pos] = true
+			usage := s.code(code)
+			usage.Sites = append(usage.Sites, Site{Position: position(pkg.Fset, id.Pos()), Call: callName(fn)})
+		}
+		return true
+	})
+}
+
+// constructedCode returns the code of the error constructed by a call to an
+// errx function, either passed as its Code argument or implied by its name
+// (NewNotFound)
+func constructedCode(info *types.Info, fn *types.Func, call *ast.CallExpr) (errx.Code, bool) {
+	sig := fn.Signature()
+	if constructors[fn.Name()] {
+		for i := 0; i < sig.Params().Len() && i < len(call.Args); i++ {
+			if isCode(sig.Params().At(i).Type()) {
+				return constantCode(info, call.Args[i])
+			}
+		}
+		return "", false
+	}
+
+	name, ok := strings.CutPrefix(fn.Name(), "New")
+	if !ok || sig.Params().Len() != 0 {
+		return "", false
+	}
+	c, ok := fn.Pkg().Scope().Lookup(name).(*types.Const)
+	if !ok || !isCode(c.Type()) {
+		return "", false
+	}
+	return errx.Code(constant.StringVal(c.Val())), true
+}
+
+// constantCode returns the value of a constant Code expression
+func constantCode(info *types.Info, expr ast.Expr) (errx.Code, bool) {
+	tv, ok := info.Types[expr]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+		return "", false
+	}
+	return errx.Code(constant.StringVal(tv.Value)), true
+}
+
+// report sorts the collected usages and derives the problem lists
+func (s *scanner) report() *Report {
+	r := &Report{}
+	for _, usage := range s.codes {
+		slices.SortFunc(usage.Sites, func(a, b Site) int { return strings.Compare(a.Position, b.Position) })
+		r.Codes = append(r.Codes, *usage)
+	}
+	slices.SortFunc(r.Codes, func(a, b CodeUsage) int { return strings.Compare(string(a.Code), string(b.Code)) })
+
+	for _, usage := range r.Codes {
+		switch {
+		case !usage.Registered:
+			r.Unregistered = append(r.Unregistered, usage.Code)
+		case usage.RegisteredAt != "" && len(usage.Sites) == 0:
+			r.Unused = append(r.Unused, usage.Code)
+		}
+	}
+	return r
+}
+
+// callee returns the function or method called, if statically known, and
+// the identifier naming it
+func callee(info *types.Info, call *ast.CallExpr) (*types.Func, *ast.Ident) {
+	var id *ast.Ident
+	switch fun := ast.Unparen(call.Fun).(type) {
+	case *ast.Ident:
+		id = fun
+	case *ast.SelectorExpr:
+		id = fun.Sel
+	default:
+		return nil, nil
+	}
+	fn, _ := info.Uses[id].(*types.Func)
+	return fn, id
+}
+
+// callName returns the name of an errx function or method as written by
+// callers, e.g. "errx.Wrap" or "(*errx.Builder).WithCode"
+func callName(fn *types.Func) string {
+	recv := fn.Signature().Recv()
+	if recv == nil {
+		return "errx." + fn.Name()
+	}
+	qualifier := func(pkg *types.Package) string { return pkg.Name() }
+	return "(" + types.TypeString(recv.Type(), qualifier) + ")." + fn.Name()
+}
+
+// isCode reports whether t is errx.Code
+func isCode(t types.Type) bool {
+	named, ok := t.(*types.Named)
+	return ok && named.Obj().Name() == "Code" && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == errxPath
+}
+
+// position formats pos relative to the working directory
+func position(fset *token.FileSet, pos token.Pos) string {
+	p := fset.Position(pos)
+	if wd, err := os.Getwd(); err == nil {
+		if rel, err := filepath.Rel(wd, p.Filename); err == nil && !strings.HasPrefix(rel, "..") {
+			p.Filename = rel
+		}
+	}
+	return p.String()
+}
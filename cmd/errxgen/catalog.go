@@ -0,0 +1,163 @@
+package main
+
+import (
+	"fmt"
+	"go/token"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Catalog is the error catalog file describing a package's codes
+type Catalog struct {
+	Package string      `yaml:"package"` // Package of the generated file, $GOPACKAGE if empty
+	Codes   []CodeEntry `yaml:"codes"`
+}
+
+// CodeEntry describes a single code of the catalog
+type CodeEntry struct {
+	Code        string `yaml:"code"`        // Code string, e.g. "QUOTA_EXCEEDED"
+	Name        string `yaml:"name"`        // Go identifier, derived from the code if empty
+	Numeric     int    `yaml:"numeric"`     // Stable numeric identifier
+	HTTPStatus  int    `yaml:"http_status"` // HTTP status
+	GRPCCode    string `yaml:"grpc_code"`   // gRPC code name ("NotFound") or number
+	Message     string `yaml:"message"`     // Default message
+	Description string `yaml:"description"` // Human-readable description
+	DocURL      string `yaml:"doc_url"`     // Documentation URL
+	Severity    string `yaml:"severity"`    // info, warning, error or critical
+	Retryable   *bool  `yaml:"retryable"`   // Default retryability
+
+	grpcCode uint32
+}
+
+// grpcCodes maps the names of google.golang.org/grpc/codes values to numbers
+var grpcCodes = map[string]uint32{
+	"OK":                 0,
+	"Canceled":           1,
+	"Unknown":            2,
+	"InvalidArgument":    3,
+	"DeadlineExceeded":   4,
+	"NotFound":           5,
+	"AlreadyExists":      6,
+	"PermissionDenied":   7,
+	"ResourceExhausted":  8,
+	"FailedPrecondition": 9,
+	"Aborted":            10,
+	"OutOfRange":         11,
+	"Unimplemented":      12,
+	"Internal":           13,
+	"Unavailable":        14,
+	"DataLoss":           15,
+	"Unauthenticated":    16,
+}
+
+// severities maps catalog severities to errx.Severity constants
+var severities = map[string]string{
+	"info":     "SeverityInfo",
+	"warning":  "SeverityWarning",
+	"error":    "SeverityError",
+	"critical": "SeverityCritical",
+}
+
+// parseCatalog parses a YAML or JSON catalog and validates its entries
+func parseCatalog(data []byte) (*Catalog, error) {
+	var c Catalog
+	if err := yaml.Unmarshal(data, &c); err != nil {
+		return nil, err
+	}
+	if len(c.Codes) == 0 {
+		return nil, fmt.Errorf("catalog has no codes")
+	}
+
+	codes := make(map[string]bool)
+	names := make(map[string]bool)
+	numerics := make(map[int]string)
+	for i := range c.Codes {
+		entry := &c.Codes[i]
+		if err := entry.validate(); err != nil {
+			return nil, fmt.Errorf("code %d (%q): %w", i+1, entry.Code, err)
+		}
+
+		if codes[entry.Code] {
+			return nil, fmt.Errorf("duplicate code %q", entry.Code)
+		}
+		codes[entry.Code] = true
+		if names[entry.Name] {
+			return nil, fmt.Errorf("duplicate name %q", entry.Name)
+		}
+		names[entry.Name] = true
+		if entry.Numeric != 0 {
+			if other, ok := numerics[entry.Numeric]; ok {
+				return nil, fmt.Errorf("codes %q and %q share numeric code %d", other, entry.Code, entry.Numeric)
+			}
+			numerics[entry.Numeric] = entry.Code
+		}
+	}
+	return &c, nil
+}
+
+// validate checks the entry and fills in derived values
+func (e *CodeEntry) validate() error {
+	if e.Code == "" {
+		return fmt.Errorf("missing code")
+	}
+	if e.Name == "" {
+		e.Name = identifier(e.Code)
+	}
+	if !token.IsIdentifier(e.Name) || !token.IsExported(e.Name) {
+		return fmt.Errorf("name %q is not an exported identifier", e.Name)
+	}
+	if e.Numeric < 0 {
+		return fmt.Errorf("negative numeric code %d", e.Numeric)
+	}
+	if e.HTTPStatus != 0 && http.StatusText(e.HTTPStatus) == "" {
+		return fmt.Errorf("unknown HTTP status %d", e.HTTPStatus)
+	}
+	if e.GRPCCode != "" {
+		code, ok := grpcCodes[e.GRPCCode]
+		if !ok {
+			n, err := strconv.ParseUint(e.GRPCCode, 10, 32)
+			if err != nil || n > 16 {
+				return fmt.Errorf("unknown gRPC code %q", e.GRPCCode)
+			}
+			code = uint32(n)
+		}
+		e.grpcCode = code
+	}
+	if _, ok := severities[e.Severity]; e.Severity != "" && !ok {
+		return fmt.Errorf("unknown severity %q", e.Severity)
+	}
+	return nil
+}
+
+// GRPCNumber returns the numeric gRPC code of the entry
+func (e *CodeEntry) GRPCNumber() uint32 {
+	return e.grpcCode
+}
+
+// GRPCName returns the google.golang.org/grpc/codes name of the entry's gRPC code
+func (e *CodeEntry) GRPCName() string {
+	for name, code := range grpcCodes {
+		if code == e.grpcCode {
+			return name
+		}
+	}
+	return ""
+}
+
+// identifier converts a code such as "QUOTA_EXCEEDED" or "quota-exceeded"
+// into a Go identifier such as "QuotaExceeded"
+func identifier(code string) string {
+	var b strings.Builder
+	for _, word := range strings.FieldsFunc(code, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		runes := []rune(strings.ToLower(word))
+		runes[0] = unicode.ToUpper(runes[0])
+		b.WriteString(string(runes))
+	}
+	return b.String()
+}
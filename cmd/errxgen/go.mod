@@ -0,0 +1,5 @@
+module github.com/nordew/go-errx/cmd/errxgen
+
+go 1.24.1
+
+require gopkg.in/yaml.v3 v3.0.1
@@ -0,0 +1,149 @@
+// Command errxgen generates errx code constants, constructors and registry
+// registration from a YAML or JSON error catalog
+//
+// Usage:
+//
+//	//go:generate go run github.com/nordew/go-errx/cmd/errxgen -in errors.yaml
+//
+// A catalog lists the codes of a package:
+//
+//	package: billing
+//	codes:
+//	  - code: QUOTA_EXCEEDED
+//	    numeric: 4295
+//	    http_status: 429
+//	    grpc_code: ResourceExhausted
+//	    message: quota exceeded
+//	    description: Account quota exceeded
+//	    doc_url: https://docs.example.com/errors/quota-exceeded
+//	    severity: warning
+//	    retryable: true
+//
+// For each code the generated file declares an errx.Code constant named
+// after it (QuotaExceeded), a NewQuotaExceeded builder constructor, and an
+// init function registering the code with errx.RegisterCode
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+func main() {
+	in := flag.String("in", "errors.yaml", "catalog file to read")
+	out := flag.String("out", "", "file to write, the catalog name with a _gen.go suffix if empty")
+	pkg := flag.String("package", "", "package of the generated file, overriding the catalog")
+	flag.Parse()
+
+	if err := run(*in, *out, *pkg); err != nil {
+		fmt.Fprintln(os.Stderr, "errxgen:", err)
+		os.Exit(1)
+	}
+}
+
+// run generates the file for the catalog at in
+func run(in, out, pkg string) error {
+	data, err := os.ReadFile(in)
+	if err != nil {
+		return err
+	}
+	catalog, err := parseCatalog(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", in, err)
+	}
+
+	if pkg != "" {
+		catalog.Package = pkg
+	}
+	if catalog.Package == "" {
+		catalog.Package = os.Getenv("GOPACKAGE")
+	}
+	if catalog.Package == "" {
+		return fmt.Errorf("%s: no package given in the catalog, -package or $GOPACKAGE", in)
+	}
+	if out == "" {
+		out = strings.TrimSuffix(in, filepath.Ext(in)) + "_gen.go"
+	}
+
+	src, err := generate(filepath.Base(in), catalog)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(out, src, 0o644)
+}
+
+// generate renders and formats the Go source for a catalog
+func generate(source string, catalog *Catalog) ([]byte, error) {
+	var buf bytes.Buffer
+	err := fileTemplate.Execute(&buf, struct {
+		Source string
+		*Catalog
+	}{source, catalog})
+	if err != nil {
+		return nil, err
+	}
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated code: %w", err)
+	}
+	return src, nil
+}
+
+var fileTemplate = template.Must(template.New("file").Funcs(template.FuncMap{
+	"severity": func(s string) string { return severities[s] },
+}).Parse(`// Code generated by errxgen from {{.Source}}. DO NOT EDIT.
+
+package {{.Package}}
+
+import "github.com/nordew/go-errx"
+
+// Error codes
+const (
+{{- range .Codes}}
+	{{.Name}} errx.Code = {{printf "%q" .Code}}{{if .Description}} // {{.Description}}{{end}}
+{{- end}}
+)
+
+func init() {
+{{- range .Codes}}
+	errx.RegisterCode({{.Name}},
+	{{- if .Numeric}}
+		errx.WithNumericCode({{.Numeric}}),
+	{{- end}}
+	{{- if .HTTPStatus}}
+		errx.WithHTTPStatus({{.HTTPStatus}}),
+	{{- end}}
+	{{- if .GRPCCode}}
+		errx.WithGRPCCode({{.GRPCNumber}}), // codes.{{.GRPCName}}
+	{{- end}}
+	{{- if .Severity}}
+		errx.WithDefaultSeverity(errx.{{severity .Severity}}),
+	{{- end}}
+	{{- if .Retryable}}
+		errx.WithDefaultRetryable({{.Retryable}}),
+	{{- end}}
+	{{- if .Description}}
+		errx.WithCodeDescription({{printf "%q" .Description}}),
+	{{- end}}
+	{{- if .Message}}
+		errx.WithDefaultMessage({{printf "%q" .Message}}),
+	{{- end}}
+	{{- if .DocURL}}
+		errx.WithDocURL({{printf "%q" .DocURL}}),
+	{{- end}}
+	)
+{{- end}}
+}
+{{range .Codes}}
+// New{{.Name}} creates an error builder for {{.Name}} errors
+func New{{.Name}}() *errx.Builder {
+	return errx.New({{.Name}})
+}
+{{end}}`))
@@ -0,0 +1,145 @@
+package errx
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// ConfigOption changes package-wide behavior with Configure
+type ConfigOption func()
+
+// Configure applies package-wide settings
+// Libraries should leave these to the application; options can be applied
+// at any time but are meant to be set once during start-up
+//
+//	errx.Configure(
+//		errx.WithStackCapture(true),
+//		errx.WithStackDepth(16),
+//		errx.WithFallbackCode(errx.Unavailable),
+//	)
+func Configure(opts ...ConfigOption) {
+	for _, opt := range opts {
+		opt()
+	}
+}
+
+var (
+	captureStack atomic.Bool
+	stackDepth   atomic.Int32
+	formatter    atomic.Pointer[func(fmt.State, *Error)]
+	clock        atomic.Pointer[func() time.Time]
+	idGenerator  atomic.Pointer[func() string]
+	fallbackCode atomic.Pointer[Code]
+)
+
+// WithStackCapture records a stack trace for every error built, not only
+// those built with WithStack; disabled by default
+func WithStackCapture(enabled bool) ConfigOption {
+	return func() {
+		captureStack.Store(enabled)
+	}
+}
+
+// WithStackDepth sets the maximum number of frames recorded for a stack
+// trace; values below 1 restore the default of 32
+func WithStackDepth(depth int) ConfigOption {
+	return func() {
+		stackDepth.Store(int32(max(depth, 0)))
+	}
+}
+
+// WithSourceCapture enables or disables recording call sites, as
+// SetCaptureSource does
+func WithSourceCapture(enabled bool) ConfigOption {
+	return func() {
+		SetCaptureSource(enabled)
+	}
+}
+
+// WithTimeCapture enables or disables recording creation times, as
+// SetCaptureTime does
+func WithTimeCapture(enabled bool) ConfigOption {
+	return func() {
+		SetCaptureTime(enabled)
+	}
+}
+
+// WithFormatter replaces the verbose form errors print with %+v
+// Passing nil restores the default, which lists every layer of the chain with
+// its stack frames or call site
+func WithFormatter(fn func(s fmt.State, e *Error)) ConfigOption {
+	return func() {
+		if fn == nil {
+			formatter.Store(nil)
+			return
+		}
+		formatter.Store(&fn)
+	}
+}
+
+// WithSanitizer installs the sanitizer, as SetSanitizer does
+func WithSanitizer(fn func(string) string) ConfigOption {
+	return func() {
+		SetSanitizer(fn)
+	}
+}
+
+// WithClock sets the function returning creation times when time capture is
+// enabled, also used for report rate limits; nil restores time.Now
+func WithClock(fn func() time.Time) ConfigOption {
+	return func() {
+		if fn == nil {
+			clock.Store(nil)
+			return
+		}
+		clock.Store(&fn)
+	}
+}
+
+// WithIDGenerator sets the function NewID uses; nil restores random IDs
+func WithIDGenerator(fn func() string) ConfigOption {
+	return func() {
+		if fn == nil {
+			idGenerator.Store(nil)
+			return
+		}
+		idGenerator.Store(&fn)
+	}
+}
+
+// WithFallbackCode sets the code GetCode reports for errors that aren't Error
+// values; an empty code restores Internal
+func WithFallbackCode(code Code) ConfigOption {
+	return func() {
+		if code == "" {
+			fallbackCode.Store(nil)
+			return
+		}
+		fallbackCode.Store(&code)
+	}
+}
+
+// currentStackDepth returns the configured stack depth
+func currentStackDepth() int {
+	if depth := stackDepth.Load(); depth > 0 {
+		return int(depth)
+	}
+	return maxStackDepth
+}
+
+// currentTime returns the current time from the configured clock
+func currentTime() time.Time {
+	if fn := clock.Load(); fn != nil {
+		return (*fn)()
+	}
+	return time.Now()
+}
+
+// currentFallbackCode returns the code of errors that aren't Error values
+func currentFallbackCode() Code {
+	if code := fallbackCode.Load(); code != nil {
+		return *code
+	}
+	return Internal
+}
@@ -0,0 +1,134 @@
+// Package connectx converts between errx errors and connect errors
+package connectx
+
+import (
+	"context"
+	"errors"
+
+	"connectrpc.com/connect"
+	"github.com/nordew/go-errx"
+	"github.com/nordew/go-errx/grpcx"
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// ToConnectError converts an error into a connect error
+// The code, fields, field errors and retry delay are attached as the same
+// details grpcx.ToStatus uses, so FromConnectError and gRPC clients can
+// restore them; connect errors are returned unchanged
+// Returns nil for a nil error
+func ToConnectError(err error) *connect.Error {
+	if err == nil {
+		return nil
+	}
+
+	var ce *connect.Error
+	var e *errx.Error
+	if errors.As(err, &ce) && !errors.As(err, &e) {
+		return ce
+	}
+
+	st := grpcx.ToStatus(err)
+	ce = connect.NewError(connect.Code(st.Code()), errors.New(st.Message()))
+	for _, detail := range st.Details() {
+		msg, ok := detail.(proto.Message)
+		if !ok {
+			continue
+		}
+		if d, detailErr := connect.NewErrorDetail(msg); detailErr == nil {
+			ce.AddDetail(d)
+		}
+	}
+	return ce
+}
+
+// FromConnectError converts a connect error back into an Error, restoring the
+// errx code, fields, field errors and retry delay from its details
+// Errors that aren't connect errors are returned as Internal errors wrapping them
+// Returns nil for a nil error
+func FromConnectError(err error) *errx.Error {
+	if err == nil {
+		return nil
+	}
+
+	var ce *connect.Error
+	if !errors.As(err, &ce) {
+		return errx.Wrap(err, errx.Internal, "")
+	}
+
+	pb := &spb.Status{
+		Code:    int32(ce.Code()),
+		Message: ce.Message(),
+	}
+	for _, d := range ce.Details() {
+		pb.Details = append(pb.Details, &anypb.Any{
+			TypeUrl: "type.googleapis.com/" + d.Type(),
+			Value:   d.Bytes(),
+		})
+	}
+	return grpcx.FromStatus(status.FromProto(pb))
+}
+
+// NewInterceptor returns a connect interceptor that converts errors returned
+// by handlers into connect errors with ToConnectError, and errors received by
+// clients into errx errors with FromConnectError
+func NewInterceptor() connect.Interceptor {
+	return interceptor{}
+}
+
+type interceptor struct{}
+
+// WrapUnary implements connect.Interceptor
+func (interceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		resp, err := next(ctx, req)
+		if err == nil {
+			return resp, nil
+		}
+		if req.Spec().IsClient {
+			return resp, FromConnectError(err)
+		}
+		return resp, ToConnectError(err)
+	}
+}
+
+// WrapStreamingClient implements connect.Interceptor
+func (interceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return func(ctx context.Context, spec connect.Spec) connect.StreamingClientConn {
+		return &clientConn{StreamingClientConn: next(ctx, spec)}
+	}
+}
+
+// WrapStreamingHandler implements connect.Interceptor
+func (interceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		if err := next(ctx, conn); err != nil {
+			return ToConnectError(err)
+		}
+		return nil
+	}
+}
+
+// clientConn converts the errors received by a streaming client
+type clientConn struct {
+	connect.StreamingClientConn
+}
+
+func (c *clientConn) Receive(msg any) error {
+	return fromStreamError(c.StreamingClientConn.Receive(msg))
+}
+
+func (c *clientConn) CloseResponse() error {
+	return fromStreamError(c.StreamingClientConn.CloseResponse())
+}
+
+// fromStreamError converts a stream error, leaving io.EOF and nil untouched
+func fromStreamError(err error) error {
+	var ce *connect.Error
+	if !errors.As(err, &ce) {
+		return err
+	}
+	return FromConnectError(err)
+}
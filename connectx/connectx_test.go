@@ -0,0 +1,113 @@
+package connectx
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/nordew/go-errx"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func TestToConnectErrorRoundTrip(t *testing.T) {
+	err := errx.New(errx.Validation).
+		WithMessage("invalid order").
+		WithField("order_id", "o-1").
+		WithFieldError("email", "format", "must be an email").
+		Error()
+
+	ce := ToConnectError(err)
+	if ce.Code() != connect.CodeInvalidArgument || ce.Message() != "invalid order" {
+		t.Errorf("ToConnectError = %s %q, want invalid_argument %q", ce.Code(), ce.Message(), "invalid order")
+	}
+
+	back := FromConnectError(ce)
+	if back.Code != errx.Validation || back.Message != "invalid order" {
+		t.Errorf("FromConnectError = %v, want [VALIDATION] invalid order", back)
+	}
+	if back.Fields["order_id"] != "o-1" {
+		t.Errorf("fields = %v, want order_id o-1", back.Fields)
+	}
+	if fe := errx.FieldErrors(back); len(fe) != 1 || fe[0].Field != "email" {
+		t.Errorf("field errors = %v, want the email error", fe)
+	}
+}
+
+func TestToConnectErrorMasksServerErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		code connect.Code
+	}{
+		{"internal", errx.New(errx.Internal).WithMessage("query failed: password=hunter2").WithField("dsn", "db-7").Error(), connect.CodeInternal},
+		{"panic", errx.FromPanic("secret"), connect.CodeInternal},
+		{"unavailable", errx.New(errx.Unavailable).WithMessage("pool exhausted").WithRetryAfter(2 * time.Second).Error(), connect.CodeUnavailable},
+		{"plain error", errors.New("dial tcp 10.0.0.5: refused"), connect.CodeInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ce := ToConnectError(tt.err)
+			if ce.Code() != tt.code {
+				t.Errorf("code = %s, want %s", ce.Code(), tt.code)
+			}
+			if status := errx.HTTPStatus(tt.err); ce.Message() != http.StatusText(status) {
+				t.Errorf("message = %q, want %q", ce.Message(), http.StatusText(status))
+			}
+			for _, secret := range []string{"hunter2", "db-7", "secret", "10.0.0.5"} {
+				for _, d := range ce.Details() {
+					if strings.Contains(string(d.Bytes()), secret) {
+						t.Errorf("detail %s leaks %q", d.Type(), secret)
+					}
+				}
+			}
+
+			back := FromConnectError(ce)
+			if back.Code != errx.GetCode(tt.err) {
+				t.Errorf("FromConnectError code = %s, want %s", back.Code, errx.GetCode(tt.err))
+			}
+			if d, ok := errx.RetryAfter(tt.err); ok {
+				if got, _ := errx.RetryAfter(back); got != d {
+					t.Errorf("retry after = %s, want %s", got, d)
+				}
+			}
+		})
+	}
+}
+
+func TestToConnectErrorKeepsConnectErrors(t *testing.T) {
+	ce := connect.NewError(connect.CodeAborted, errors.New("conflict"))
+	if got := ToConnectError(ce); got != ce {
+		t.Errorf("ToConnectError = %v, want the connect error unchanged", got)
+	}
+	if ToConnectError(nil) != nil {
+		t.Error("ToConnectError(nil) != nil")
+	}
+}
+
+func TestFromConnectErrorPlainError(t *testing.T) {
+	plain := errors.New("plain")
+	err := FromConnectError(plain)
+	if err.Code != errx.Internal || !errors.Is(err, plain) {
+		t.Errorf("FromConnectError = %v, want an Internal error wrapping %v", err, plain)
+	}
+	if FromConnectError(nil) != nil {
+		t.Error("FromConnectError(nil) != nil")
+	}
+}
+
+func TestInterceptorHandler(t *testing.T) {
+	next := func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		return nil, errx.NewNotFound().WithMessage("user not found").Error()
+	}
+
+	_, err := NewInterceptor().WrapUnary(next)(context.Background(), connect.NewRequest(&emptypb.Empty{}))
+	var ce *connect.Error
+	if !errors.As(err, &ce) || ce.Code() != connect.CodeNotFound || ce.Message() != "user not found" {
+		t.Errorf("handler error = %v, want a not_found connect error", err)
+	}
+}
@@ -0,0 +1,39 @@
+package errx
+
+import (
+	"context"
+	"errors"
+)
+
+// FromContextErr converts a context error into an Error that wraps it,
+// mapping context.Canceled to Cancelled and context.DeadlineExceeded to Timeout
+// Returns nil if err is nil or isn't a context error
+func FromContextErr(err error) *Error {
+	code := contextCode(err, "")
+	if code == "" {
+		return nil
+	}
+	return New(code).WithCause(err).build(1)
+}
+
+// contextCode returns the code describing a context error found in err's
+// chain, or code if there's none
+func contextCode(err error, code Code) Code {
+	switch {
+	case errors.Is(err, context.Canceled):
+		return Cancelled
+	case errors.Is(err, context.DeadlineExceeded):
+		return Timeout
+	}
+	return code
+}
+
+// wrapCode returns the code to use when wrapping err with code
+// Internal is replaced by the context error's code so cancellations and
+// deadlines aren't reported as internal failures
+func wrapCode(err error, code Code) Code {
+	if code != Internal {
+		return code
+	}
+	return contextCode(err, code)
+}
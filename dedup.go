@@ -0,0 +1,95 @@
+package errx
+
+import (
+	"maps"
+	"sync"
+	"time"
+)
+
+// OccurrencesField is the field holding the number of occurrences on the
+// report summarizing duplicates coalesced by WithReportDedup
+const OccurrencesField = "occurrences"
+
+// dedup coalesces reports of errors with the same fingerprint
+type dedup struct {
+	mu      sync.Mutex
+	ttl     time.Duration // Length of a suppression window, 0 when disabled
+	windows map[string]*dedupWindow
+}
+
+// dedupWindow tracks the occurrences of one fingerprint since its first report
+type dedupWindow struct {
+	count int    // Occurrences in the window, including the reported one
+	last  *Error // Most recent suppressed occurrence
+}
+
+var reportDedup = &dedup{}
+
+// WithReportDedup coalesces errors with the same fingerprint reported within
+// ttl of each other, so a retry storm produces one report instead of
+// thousands
+// The first occurrence is reported immediately; if more follow within the
+// window, the last one is reported again when it closes, from another
+// goroutine, with the total number of occurrences in OccurrencesField
+// A ttl of 0 disables it
+func WithReportDedup(ttl time.Duration) ConfigOption {
+	return func() {
+		d := reportDedup
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		d.ttl = max(ttl, 0)
+		d.windows = nil
+	}
+}
+
+// admit reports whether an error with the fingerprint should be reported now,
+// counting it as a duplicate otherwise
+func (d *dedup) admit(key string, e *Error) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.ttl == 0 {
+		return true
+	}
+
+	if w, ok := d.windows[key]; ok {
+		w.count++
+		w.last = e
+		return false
+	}
+	if d.windows == nil {
+		d.windows = make(map[string]*dedupWindow)
+	}
+	windows := d.windows
+	w := &dedupWindow{count: 1}
+	windows[key] = w
+	time.AfterFunc(d.ttl, func() { d.flush(windows, key, w) })
+	return true
+}
+
+// flush closes the window of a fingerprint, reporting a summary of its
+// duplicates if there were any
+func (d *dedup) flush(windows map[string]*dedupWindow, key string, w *dedupWindow) {
+	d.mu.Lock()
+	delete(windows, key)
+	count, last := w.count, w.last
+	d.mu.Unlock()
+
+	if last == nil {
+		return
+	}
+	if box := reporter.Load(); box != nil {
+		box.r.Report(withOccurrences(last, count))
+	}
+}
+
+// withOccurrences returns a copy of e carrying the number of occurrences,
+// leaving the error held by its creator untouched
+func withOccurrences(e *Error, count int) *Error {
+	summary := *e
+	summary.Fields = maps.Clone(e.Fields)
+	if summary.Fields == nil {
+		summary.Fields = make(map[string]any, 1)
+	}
+	summary.Fields[OccurrencesField] = count
+	return &summary
+}
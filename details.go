@@ -0,0 +1,93 @@
+package errx
+
+import (
+	"maps"
+	"slices"
+	"sync/atomic"
+)
+
+// MergePolicy decides which value Details keeps when layers of a chain set
+// the same key
+type MergePolicy int
+
+// Merge policies
+const (
+	OuterWins MergePolicy = iota + 1 // Wrapping layers override their causes, the default
+	InnerWins                        // The layer closest to the original failure is kept
+)
+
+// String returns a readable name of the policy
+func (p MergePolicy) String() string {
+	switch p {
+	case OuterWins:
+		return "outer wins"
+	case InnerWins:
+		return "inner wins"
+	}
+	return "unknown"
+}
+
+var detailsMerge atomic.Int32
+
+// WithDetailsMerge sets how Details resolves keys set by several layers;
+// 0 restores OuterWins
+func WithDetailsMerge(p MergePolicy) ConfigOption {
+	return func() {
+		detailsMerge.Store(int32(p))
+	}
+}
+
+// currentDetailsMerge returns the configured merge policy
+func currentDetailsMerge() MergePolicy {
+	if p := detailsMerge.Load(); p != 0 {
+		return MergePolicy(p)
+	}
+	return OuterWins
+}
+
+// WithDetails merges details into the error's details, values passed later
+// overriding earlier ones
+// Unlike fields, details are merged across the chain by Details, which makes
+// them suited to bulk context such as the attributes of a request
+func (b *Builder) WithDetails(details map[string]any) *Builder {
+	if len(details) == 0 {
+		return b
+	}
+	if b.details == nil {
+		b.details = make(map[string]any, len(details))
+	}
+	maps.Copy(b.details, details)
+	return b
+}
+
+// Details returns the details set on this layer with WithDetails, without
+// those of its causes
+func (e *Error) Details() map[string]any {
+	return e.details
+}
+
+// Details returns the details of every Error in the chain merged into one
+// map, keys set by several layers resolved by the policy set with
+// WithDetailsMerge
+// Returns nil if no layer has details
+func Details(err error) map[string]any {
+	var layers []map[string]any
+	for e := range ChainErrx(err) {
+		if len(e.details) > 0 {
+			layers = append(layers, e.details)
+		}
+	}
+	if len(layers) == 0 {
+		return nil
+	}
+
+	// Copy the winning layer last
+	if currentDetailsMerge() == OuterWins {
+		slices.Reverse(layers)
+	}
+	merged := make(map[string]any)
+	for _, details := range layers {
+		maps.Copy(merged, details)
+	}
+	return merged
+}
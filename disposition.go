@@ -0,0 +1,150 @@
+package errx
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+)
+
+// MessageAction tells a message consumer what to do with a message whose
+// processing failed
+type MessageAction int
+
+// Message actions
+const (
+	Retry      MessageAction = iota + 1 // Leave the message for redelivery, it may succeed later
+	DeadLetter                          // Move the message to a dead-letter queue, it will never succeed as is
+	Skip                                // Acknowledge the message and drop it, there is nothing left to do
+)
+
+// String returns the lowercase name of the action
+func (a MessageAction) String() string {
+	switch a {
+	case Retry:
+		return "retry"
+	case DeadLetter:
+		return "dead_letter"
+	case Skip:
+		return "skip"
+	}
+	return "unknown"
+}
+
+// WithDisposition sets the action taken by message consumers on errors with
+// the code, overriding the classification of Disposition
+func WithDisposition(a MessageAction) CodeOption {
+	return func(info *CodeInfo) {
+		info.Disposition = a
+	}
+}
+
+// Disposition classifies a message processing failure so consumers handle
+// poison messages consistently
+// The action registered for the code with WithDisposition takes precedence,
+// followed by an explicit WithRetryable flag; otherwise transient errors,
+// timeouts and cancellations, which usually mean the consumer is shutting
+// down, are retried, AlreadyExists and Gone errors are skipped as the message
+// was already applied or its target no longer exists, and every other error,
+// including Internal errors and recovered panics, is dead-lettered so a
+// poison message isn't redelivered forever
+// Returns Skip for a nil error
+func Disposition(err error) MessageAction {
+	if err == nil {
+		return Skip
+	}
+
+	code := GetCode(err)
+	if info, ok := LookupCode(code); ok && info.Disposition != 0 {
+		return info.Disposition
+	}
+	if retryable, ok := RetryableFlag(err); ok {
+		if retryable {
+			return Retry
+		}
+		return DeadLetter
+	}
+
+	switch {
+	case CodeCategories(code).Has(CategoryTransient), code == Cancelled,
+		errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded), netTimeout(err):
+		return Retry
+	case code == AlreadyExists, code == Gone:
+		return Skip
+	}
+	return DeadLetter
+}
+
+// Headers describing a failure on a dead-letter record
+const (
+	DeadLetterCodeHeader        = "errx-code"
+	DeadLetterNumericHeader     = "errx-numeric-code"
+	DeadLetterMessageHeader     = "errx-message"
+	DeadLetterErrorHeader       = "errx-error"
+	DeadLetterFieldsHeader      = "errx-fields"
+	DeadLetterIDHeader          = "errx-id"
+	DeadLetterFingerprintHeader = "errx-fingerprint"
+	DeadLetterRetryableHeader   = "errx-retryable"
+	DeadLetterTimeHeader        = "errx-time"
+)
+
+// DeadLetterHeaders describes err as headers for a dead-letter record, so the
+// failure can be inspected and replayed without the consumer's logs
+// Messages, the error text and fields go through the installed sanitizer;
+// fields are encoded as JSON, the time as RFC 3339 and the retryable flag is
+// whether Disposition retries the error, so the two never contradict
+// Headers without a value are omitted; returns nil for a nil error
+func DeadLetterHeaders(err error) map[string]string {
+	if err == nil {
+		return nil
+	}
+
+	headers := map[string]string{
+		DeadLetterCodeHeader:        string(GetCode(err)),
+		DeadLetterNumericHeader:     strconv.Itoa(NumericCode(err)),
+		DeadLetterMessageHeader:     Sanitize(GetMessage(err)),
+		DeadLetterErrorHeader:       Sanitize(err.Error()),
+		DeadLetterFingerprintHeader: Fingerprint(err),
+		DeadLetterRetryableHeader:   strconv.FormatBool(Disposition(err) == Retry),
+	}
+	if fields := SanitizeFields(GetFields(err)); len(fields) > 0 {
+		if data, jerr := json.Marshal(fields); jerr == nil {
+			headers[DeadLetterFieldsHeader] = string(data)
+		}
+	}
+	if id := GetID(err); id != "" {
+		headers[DeadLetterIDHeader] = id
+	}
+	if t := GetTime(err); !t.IsZero() {
+		headers[DeadLetterTimeHeader] = t.Format(time.RFC3339Nano)
+	}
+	return headers
+}
+
+// FromDeadLetterHeaders restores the error described by DeadLetterHeaders,
+// for tools inspecting or replaying dead-lettered messages
+// Unknown headers are ignored and malformed values left unset
+// Returns nil if the headers have no code
+func FromDeadLetterHeaders(headers map[string]string) *RemoteError {
+	code := Code(headers[DeadLetterCodeHeader])
+	if code == "" {
+		return nil
+	}
+
+	e := &RemoteError{
+		Code:    code,
+		ID:      headers[DeadLetterIDHeader],
+		Message: headers[DeadLetterMessageHeader],
+	}
+	if data := headers[DeadLetterFieldsHeader]; data != "" {
+		var fields map[string]any
+		if json.Unmarshal([]byte(data), &fields) == nil {
+			e.Fields = fields
+		}
+	}
+	if t, err := time.Parse(time.RFC3339Nano, headers[DeadLetterTimeHeader]); err == nil {
+		e.Time = t
+	}
+	return e
+}
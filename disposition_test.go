@@ -0,0 +1,127 @@
+package errx
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestDisposition(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want MessageAction
+	}{
+		{"nil", nil, Skip},
+		{"unavailable", New(Unavailable).Error(), Retry},
+		{"timeout", New(Timeout).Error(), Retry},
+		{"too many requests", New(TooManyRequests).Error(), Retry},
+		{"cancelled", New(Cancelled).Error(), Retry},
+		{"context canceled", fmt.Errorf("consume: %w", context.Canceled), Retry},
+		{"deadline exceeded", fmt.Errorf("consume: %w", context.DeadlineExceeded), Retry},
+		{"validation", New(Validation).Error(), DeadLetter},
+		{"bad request", New(BadRequest).Error(), DeadLetter},
+		{"not found", New(NotFound).Error(), DeadLetter},
+		{"internal", Wrap(fmt.Errorf("unexpected EOF"), Internal, "decoding payload"), DeadLetter},
+		{"panic", FromPanic("index out of range"), DeadLetter},
+		{"plain error", fmt.Errorf("boom"), DeadLetter},
+		{"internal marked retryable", New(Internal).WithRetryable(true).Error(), Retry},
+		{"transient marked non-retryable", New(Unavailable).WithRetryable(false).Error(), DeadLetter},
+		{"already exists", New(AlreadyExists).Error(), Skip},
+		{"gone", New(Gone).Error(), Skip},
+		{"wrapped duplicate", Wrap(New(AlreadyExists).Error(), AlreadyExists, "inserting order"), Skip},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Disposition(tt.err); got != tt.want {
+				t.Errorf("Disposition(%v) = %s, want %s", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDispositionRegistered(t *testing.T) {
+	const code Code = "TEST_DISPOSITION_POISON"
+	RegisterCode(code, WithDefaultRetryable(true), WithDisposition(DeadLetter))
+
+	if got := Disposition(New(code).Error()); got != DeadLetter {
+		t.Errorf("Disposition = %s, want %s", got, DeadLetter)
+	}
+}
+
+func TestDeadLetterHeaders(t *testing.T) {
+	created := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	err := New(Validation).
+		WithMessage("order is invalid").
+		WithField("order_id", "o-1").
+		WithID("abc123").
+		WithCause(fmt.Errorf("missing sku")).
+		Error()
+	err.(*Error).time = created
+
+	headers := DeadLetterHeaders(err)
+	want := map[string]string{
+		DeadLetterCodeHeader:      string(Validation),
+		DeadLetterMessageHeader:   "order is invalid",
+		DeadLetterErrorHeader:     "[VALIDATION] order is invalid: missing sku",
+		DeadLetterFieldsHeader:    `{"order_id":"o-1"}`,
+		DeadLetterIDHeader:        "abc123",
+		DeadLetterRetryableHeader: "false",
+		DeadLetterTimeHeader:      created.Format(time.RFC3339Nano),
+	}
+	for key, value := range want {
+		if headers[key] != value {
+			t.Errorf("header %s = %q, want %q", key, headers[key], value)
+		}
+	}
+
+	restored := FromDeadLetterHeaders(headers)
+	if restored == nil {
+		t.Fatal("FromDeadLetterHeaders returned nil")
+	}
+	if !IsCode(restored, Validation) || restored.Message != "order is invalid" || restored.ID != "abc123" {
+		t.Errorf("restored error = %+v", restored)
+	}
+	if restored.Fields["order_id"] != "o-1" {
+		t.Errorf("restored fields = %v", restored.Fields)
+	}
+	if !restored.Time.Equal(created) {
+		t.Errorf("restored time = %v, want %v", restored.Time, created)
+	}
+
+	if DeadLetterHeaders(nil) != nil {
+		t.Error("DeadLetterHeaders(nil) != nil")
+	}
+	if FromDeadLetterHeaders(map[string]string{}) != nil {
+		t.Error("FromDeadLetterHeaders without a code != nil")
+	}
+}
+
+func TestDeadLetterHeadersRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"internal", Wrap(fmt.Errorf("unexpected EOF"), Internal, "decoding payload"), "false"},
+		{"panic", FromPanic("index out of range"), "false"},
+		{"internal marked retryable", New(Internal).WithRetryable(true).Error(), "true"},
+		{"unavailable", New(Unavailable).Error(), "true"},
+		{"validation", New(Validation).Error(), "false"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DeadLetterHeaders(tt.err)[DeadLetterRetryableHeader]
+			if got != tt.want {
+				t.Errorf("%s = %s, want %s", DeadLetterRetryableHeader, got, tt.want)
+			}
+			if retry := Disposition(tt.err) == Retry; strconv.FormatBool(retry) != got {
+				t.Errorf("%s = %s contradicts Disposition %s", DeadLetterRetryableHeader, got, Disposition(tt.err))
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+// Package echox provides an echo.HTTPErrorHandler for errx errors
+package echox
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"github.com/labstack/echo/v4"
+	"github.com/nordew/go-errx"
+	"github.com/nordew/go-errx/httpx"
+)
+
+// Option configures the error handler
+type Option func(*options)
+
+type options struct {
+	logger   *slog.Logger
+	logCodes []errx.Code
+}
+
+// WithLogger sets the logger failed requests are logged to with
+// errx.LogContext, slog.Default() by default
+// If codes are given, errors with one of those codes are logged instead of
+// server errors (5xx)
+func WithLogger(logger *slog.Logger, codes ...errx.Code) Option {
+	return func(o *options) {
+		o.logger = logger
+		o.logCodes = codes
+	}
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{logger: slog.Default()}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// ErrorHandler returns an echo.HTTPErrorHandler that writes errors as JSON with
+// the status mapped from their code, using the same body as httpx.WriteError
+// echo.HTTPError values are converted using their status, message and internal
+// error; server errors are logged and masked from clients
+func ErrorHandler(opts ...Option) echo.HTTPErrorHandler {
+	o := newOptions(opts)
+	return func(err error, c echo.Context) {
+		if c.Response().Committed {
+			return
+		}
+
+		err = FromHTTPError(err)
+		if o.shouldLog(errx.GetCode(err)) {
+			req := c.Request()
+			errx.LogContext(req.Context(), o.logger, err,
+				slog.String("method", req.Method),
+				slog.String("path", c.Path()),
+			)
+		}
+		httpx.WriteError(c.Response(), c.Request(), err)
+	}
+}
+
+// FromHTTPError converts an echo.HTTPError into an Error with the code mapped
+// from its status, its message and its internal error as the cause
+// Other errors are returned unchanged
+func FromHTTPError(err error) error {
+	he, ok := err.(*echo.HTTPError)
+	if !ok {
+		return err
+	}
+
+	code := errx.CodeFromHTTPStatus(he.Code)
+	if code == "" {
+		code = errx.Internal
+	}
+	b := errx.New(code).WithCause(he.Internal)
+	if msg, ok := he.Message.(string); ok {
+		b.WithMessage(msg)
+	} else if he.Message != nil {
+		b.WithMessage(fmt.Sprint(he.Message))
+	}
+	return b.Build()
+}
+
+// shouldLog reports whether errors with the code are logged
+func (o *options) shouldLog(code errx.Code) bool {
+	if o.logger == nil {
+		return false
+	}
+	if len(o.logCodes) > 0 {
+		return slices.Contains(o.logCodes, code)
+	}
+	return errx.CodeHTTPStatus(code) >= 500
+}
@@ -0,0 +1,123 @@
+package echox
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/nordew/go-errx"
+	"github.com/nordew/go-errx/httpx"
+)
+
+func serve(t *testing.T, opts []Option, handler echo.HandlerFunc) *httptest.ResponseRecorder {
+	t.Helper()
+	e := echo.New()
+	e.HTTPErrorHandler = ErrorHandler(opts...)
+	e.GET("/users/:id", handler)
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/7", nil))
+	return rec
+}
+
+func decode(t *testing.T, rec *httptest.ResponseRecorder) httpx.Response {
+	t.Helper()
+	var resp httpx.Response
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decoding %s: %v", rec.Body.Bytes(), err)
+	}
+	return resp
+}
+
+func TestErrorHandler(t *testing.T) {
+	rec := serve(t, []Option{WithLogger(nil)}, func(c echo.Context) error {
+		return errx.NewNotFound().WithMessage("user not found").WithID("id-1").WithField("user_id", 7).Error()
+	})
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+	if got := rec.Header().Get(errx.CodeHeader); got != string(errx.NotFound) {
+		t.Errorf("%s = %q, want %q", errx.CodeHeader, got, errx.NotFound)
+	}
+	if got := rec.Header().Get(errx.IDHeader); got != "id-1" {
+		t.Errorf("%s = %q, want id-1", errx.IDHeader, got)
+	}
+	resp := decode(t, rec)
+	if resp.Code != errx.NotFound || resp.Message != "user not found" || resp.Fields["user_id"] != float64(7) {
+		t.Errorf("body = %s, want the not found error", rec.Body.Bytes())
+	}
+}
+
+func TestErrorHandlerMasksServerErrors(t *testing.T) {
+	for name, err := range map[string]error{
+		"internal": errx.NewInternal().WithMessage("query failed: password=hunter2").WithField("dsn", "db-7").Error(),
+		"plain":    errors.New("dial tcp 10.0.0.5: refused"),
+	} {
+		t.Run(name, func(t *testing.T) {
+			rec := serve(t, []Option{WithLogger(nil)}, func(c echo.Context) error { return err })
+
+			resp := decode(t, rec)
+			if rec.Code != http.StatusInternalServerError || resp.Message != http.StatusText(http.StatusInternalServerError) ||
+				len(resp.Fields) != 0 {
+				t.Errorf("response = %d %s, want a masked 500", rec.Code, rec.Body.Bytes())
+			}
+		})
+	}
+}
+
+func TestErrorHandlerHTTPError(t *testing.T) {
+	rec := serve(t, []Option{WithLogger(nil)}, func(c echo.Context) error {
+		return echo.NewHTTPError(http.StatusForbidden, "not yours")
+	})
+
+	resp := decode(t, rec)
+	if rec.Code != http.StatusForbidden || resp.Code != errx.Forbidden || resp.Message != "not yours" {
+		t.Errorf("response = %d %s, want [FORBIDDEN] not yours", rec.Code, rec.Body.Bytes())
+	}
+}
+
+func TestErrorHandlerKeepsCommittedResponses(t *testing.T) {
+	rec := serve(t, []Option{WithLogger(nil)}, func(c echo.Context) error {
+		c.String(http.StatusAccepted, "queued")
+		return errx.NewInternal().Error()
+	})
+
+	if rec.Code != http.StatusAccepted || rec.Body.String() != "queued" {
+		t.Errorf("response = %d %s, want the handler's 202", rec.Code, rec.Body.Bytes())
+	}
+}
+
+func TestFromHTTPError(t *testing.T) {
+	cause := errors.New("token expired")
+	err := FromHTTPError(echo.NewHTTPError(http.StatusUnauthorized, "login required").SetInternal(cause))
+	if !errx.IsCode(err, errx.Unauthorized) || errx.GetMessage(err) != "login required" || !errors.Is(err, cause) {
+		t.Errorf("FromHTTPError = %v, want [UNAUTHORIZED] login required caused by %v", err, cause)
+	}
+
+	plain := errors.New("plain")
+	if got := FromHTTPError(plain); got != plain {
+		t.Errorf("FromHTTPError(plain) = %v, want it unchanged", got)
+	}
+}
+
+func TestWithLoggerCodes(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	serve(t, []Option{WithLogger(logger)}, func(c echo.Context) error { return errx.NewNotFound().Error() })
+	if buf.Len() != 0 {
+		t.Errorf("logged %s for a client error", buf.Bytes())
+	}
+
+	serve(t, []Option{WithLogger(logger, errx.NotFound)}, func(c echo.Context) error { return errx.NewNotFound().Error() })
+	if !strings.Contains(buf.String(), `"path":"/users/:id"`) {
+		t.Errorf("log = %s, want the route path", buf.Bytes())
+	}
+}
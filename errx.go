@@ -3,11 +3,33 @@ package errx
 import (
 	"errors"
 	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"time"
 )
 
 // Code represents a categorized error type
+// Code implements error so it can be used as a sentinel:
+// errors.Is(err, errx.NotFound) reports whether err has the NotFound code
 type Code string
 
+// Error implements the error interface and returns the code itself
+func (c Code) Error() string {
+	return string(c)
+}
+
+// As allows errors.As to treat a Code returned as an error like an Error
+// with that code and its default message
+func (c Code) As(target any) bool {
+	base := &Error{Code: c, Message: DefaultMessage(c)}
+	if t, ok := target.(**Error); ok {
+		*t = base
+		return true
+	}
+	return base.As(target)
+}
+
 // Standard error codes
 const (
 	Conflict      Code = "CONFLICT"       // Resource conflicts with existing data
@@ -19,18 +41,52 @@ const (
 	Forbidden     Code = "FORBIDDEN"      // Permission denied
 	Timeout       Code = "TIMEOUT"        // Operation timed out
 	Validation    Code = "VALIDATION"     // Input validation failed
+
+	Unavailable          Code = "UNAVAILABLE"            // Service temporarily unavailable
+	TooManyRequests      Code = "TOO_MANY_REQUESTS"      // Rate limit exceeded
+	NotImplemented       Code = "NOT_IMPLEMENTED"        // Operation not implemented
+	Cancelled            Code = "CANCELLED"              // Operation cancelled by the caller
+	PreconditionFailed   Code = "PRECONDITION_FAILED"    // System not in the state required by the operation
+	ResourceExhausted    Code = "RESOURCE_EXHAUSTED"     // Quota or capacity exhausted
+	Gone                 Code = "GONE"                   // Resource permanently removed
+	PayloadTooLarge      Code = "PAYLOAD_TOO_LARGE"      // Request body too large
+	UnsupportedMediaType Code = "UNSUPPORTED_MEDIA_TYPE" // Request content type not supported
 )
 
 // Error represents an application-specific error with code and context
 type Error struct {
-	Code    Code   // Error classification code
-	Message string // User-friendly error message
-	Err     error  // Original error (if any)
+	Code    Code           // Error classification code
+	Message string         // User-friendly error message
+	Err     error          // Original error (if any)
+	Fields  map[string]any // Structured context (if any)
+
+	op             string               // Logical operation that failed
+	namespace      string               // Domain that produced the error
+	stack          stack                // Call stack captured at creation (if requested)
+	severity       Severity             // Explicit severity overriding the code default
+	retryable      *bool                // Explicit retryability overriding the code default
+	retryAfter     time.Duration        // Suggested delay before retrying
+	template       string               // Message before formatting arguments were applied
+	templateParams map[string]any       // Parameters the template was rendered with
+	fingerprint    string               // Explicit grouping key overriding the computed one
+	time           time.Time            // Creation time (if enabled with SetCaptureTime)
+	pc             uintptr              // Program counter of the call site
+	source         *Source              // Call site restored from a serialized error
+	fieldErrors    []FieldError         // Per-field validation failures
+	inlineCause    bool                 // Message already includes the text of Err
+	id             string               // Identifier of this occurrence
+	messageKey     string               // Catalog key of the localized message
+	messageParams  map[string]any       // Named parameters of the localized message
+	details        map[string]any       // Bulk context merged across the chain by Details
+	attachments    map[reflect.Type]any // Typed payloads set by Attach, keyed by type
+	challenges     []Challenge          // Authentication challenges for WWW-Authenticate
 }
 
 // Error implements the error interface and formats the error message
+// It's terse in every mode, as callers such as Errorf and fmt.Errorf embed it
+// in messages; print the error with %+v for the verbose form
 func (e *Error) Error() string {
-	if e.Err != nil {
+	if e.Err != nil && !e.inlineCause {
 		return fmt.Sprintf("[%s] %s: %v", e.Code, e.Message, e.Err)
 	}
 	return fmt.Sprintf("[%s] %s", e.Code, e.Message)
@@ -42,29 +98,68 @@ func (e *Error) Unwrap() error {
 }
 
 // Is implements error comparison for the errors.Is function
+// It matches targets that are Errors or Codes with the same code, including
+// any member of a joined target
 func (e *Error) Is(target error) bool {
-	var t *Error
-	if !errors.As(target, &t) {
-		return false
-	}
-	return e.Code == t.Code
+	return IsCode(target, e.Code)
 }
 
 // IsCode checks if an error has a specific error code
+// Each branch of a joined error (errors.Join, Join or any Unwrap() []error)
+// is checked, so a code hidden inside a joined error is still found
 func IsCode(err error, code Code) bool {
-	if err == nil {
-		return false
-	}
+	found := false
+	eachCode(err, func(c Code) bool {
+		found = c == code
+		return !found
+	})
+	return found
+}
 
-	var e *Error
-	if errors.As(err, &e) {
-		return e.Code == code
+// eachCode calls fn with the outermost code of every branch of the error
+// tree, descending into each member of multi-errors
+// Returns false if fn stopped the walk
+func eachCode(err error, fn func(Code) bool) bool {
+	for err != nil {
+		switch e := err.(type) {
+		case *Error:
+			return fn(e.Code)
+		case *RemoteError:
+			return fn(e.Code)
+		case Code:
+			return fn(e)
+		case *MultiError:
+			if !fn(e.code) {
+				return false
+			}
+		case *BatchError:
+			if !fn(e.code) {
+				return false
+			}
+		}
+
+		switch u := err.(type) {
+		case interface{ Unwrap() []error }:
+			for _, member := range u.Unwrap() {
+				if !eachCode(member, fn) {
+					return false
+				}
+			}
+			return true
+		case interface{ Unwrap() error }:
+			err = u.Unwrap()
+		default:
+			return true
+		}
 	}
-	return false
+	return true
 }
 
 // GetCode extracts the error code from an error
-// Returns Internal if the error isn't an Error type
+// Joined errors are searched depth-first, a MultiError reports its primary
+// code and a BatchError its overall code
+// Returns Internal, or the code set with WithFallbackCode, if the error isn't
+// an Error type
 func GetCode(err error) Code {
 	if err == nil {
 		return ""
@@ -74,10 +169,11 @@ func GetCode(err error) Code {
 	if errors.As(err, &e) {
 		return e.Code
 	}
-	return Internal
+	return currentFallbackCode()
 }
 
 // GetMessage extracts the user-friendly message from an error
+// Joined errors are searched the same way as GetCode
 func GetMessage(err error) string {
 	if err == nil {
 		return ""
@@ -90,16 +186,55 @@ func GetMessage(err error) string {
 	return err.Error()
 }
 
+// GetFields extracts the structured context from an error
+// Returns nil if the error isn't an Error type or has no fields
+func GetFields(err error) map[string]any {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Fields
+	}
+	return nil
+}
+
 // Builder provides a fluent API for building Errors
 type Builder struct {
-	code    Code
-	message string
-	err     error
+	code           Code
+	message        string
+	err            error
+	op             string
+	namespace      string
+	fields         map[string]any
+	severity       Severity
+	retryable      *bool
+	retryAfter     time.Duration
+	template       string
+	templateParams map[string]any
+	fingerprint    string
+	fieldErrors    []FieldError
+	inlineCause    bool
+	id             string
+	messageKey     string
+	messageParams  map[string]any
+	details        map[string]any
+	attachments    map[reflect.Type]any
+	challenges     []Challenge
+	stack          bool
+	callerSkip     int
+	origin         *Error // Error being extended by From
+	unreported     bool   // Skip observers, the reporter and the recent error buffer
+}
+
+// WithCode replaces the code the builder was created with
+func (b *Builder) WithCode(code Code) *Builder {
+	b.code = code
+	return b
 }
 
 // WithMessage sets a descriptive message for the error
 func (b *Builder) WithMessage(msg string) *Builder {
 	b.message = msg
+	b.template = msg
+	b.templateParams = nil
 	return b
 }
 
@@ -112,36 +247,184 @@ func (b *Builder) WithCause(err error) *Builder {
 // WithMessagef sets a formatted message for the error
 func (b *Builder) WithMessagef(format string, args ...interface{}) *Builder {
 	b.message = fmt.Sprintf(format, args...)
+	b.template = format
+	b.templateParams = nil
+	return b
+}
+
+// WithOp records the logical operation that failed, e.g. "userrepo.GetUser"
+func (b *Builder) WithOp(op string) *Builder {
+	b.op = op
+	return b
+}
+
+// WithNamespace tags the error with the domain that produced it, e.g. "billing"
+func (b *Builder) WithNamespace(namespace string) *Builder {
+	b.namespace = namespace
+	return b
+}
+
+// Field names used by WithResource
+const (
+	ResourceField   = "resource"
+	ResourceIDField = "resource_id"
+)
+
+// WithResource records the kind and identifier of the resource the error is about,
+// e.g. WithResource("user", id)
+func (b *Builder) WithResource(kind string, id any) *Builder {
+	return b.WithField(ResourceField, kind).WithField(ResourceIDField, id)
+}
+
+// WithField attaches a key-value pair of structured context to the error
+func (b *Builder) WithField(key string, value any) *Builder {
+	if b.fields == nil {
+		b.fields = make(map[string]any)
+	}
+	b.fields[key] = value
+	return b
+}
+
+// WithFields attaches several key-value pairs of structured context to the error
+func (b *Builder) WithFields(fields map[string]any) *Builder {
+	for key, value := range fields {
+		b.WithField(key, value)
+	}
+	return b
+}
+
+// WithSeverity overrides the default severity derived from the code
+func (b *Builder) WithSeverity(s Severity) *Builder {
+	b.severity = s
+	return b
+}
+
+// WithRetryable marks whether the failed operation may be retried,
+// overriding the default derived from the code
+func (b *Builder) WithRetryable(retryable bool) *Builder {
+	b.retryable = &retryable
+	return b
+}
+
+// WithRetryAfter suggests how long clients should wait before retrying
+func (b *Builder) WithRetryAfter(d time.Duration) *Builder {
+	b.retryAfter = d
+	return b
+}
+
+// WithFingerprint sets the grouping key returned by Fingerprint,
+// overriding the computed one
+func (b *Builder) WithFingerprint(fingerprint string) *Builder {
+	b.fingerprint = fingerprint
+	return b
+}
+
+// WithStack captures the call stack when the error is built
+func (b *Builder) WithStack() *Builder {
+	b.stack = true
+	return b
+}
+
+// WithCallerSkip skips additional frames when recording the call site and
+// stack, so helpers that build errors can report their own caller
+func (b *Builder) WithCallerSkip(skip int) *Builder {
+	b.callerSkip = skip
 	return b
 }
 
 // Build creates and returns the final Error
+// Errors built without a message use the default message registered for the code
+// Build hooks, observers, the reporter and the recent error buffer see the
+// error, unless it extends one with From or is marked with Unreported
 func (b *Builder) Build() *Error {
-	return &Error{
+	return b.build(1)
+}
+
+// Error returns the Error as an error interface type
+func (b *Builder) Error() error {
+	return b.build(1)
+}
+
+// build creates the final Error, skipping the given number of frames
+// above its caller when capturing the stack
+func (b *Builder) build(skip int) *Error {
+	if b.message == "" {
+		b.message = DefaultMessage(b.code)
+		b.template = b.message
+	}
+
+	e := &Error{
 		Code:    b.code,
 		Message: b.message,
 		Err:     b.err,
+		Fields:  maps.Clone(b.fields),
+
+		op:             b.op,
+		namespace:      b.namespace,
+		severity:       b.severity,
+		retryable:      b.retryable,
+		retryAfter:     b.retryAfter,
+		template:       b.template,
+		templateParams: maps.Clone(b.templateParams),
+		fingerprint:    b.fingerprint,
+		time:           now(),
+		pc:             callerPC(skip + 1 + b.callerSkip),
+		fieldErrors:    slices.Clone(b.fieldErrors),
+		inlineCause:    b.inlineCause,
+		id:             b.id,
+		messageKey:     b.messageKey,
+		messageParams:  maps.Clone(b.messageParams),
+		details:        maps.Clone(b.details),
+		attachments:    maps.Clone(b.attachments),
+		challenges:     slices.Clone(b.challenges),
+	}
+	if b.stack || (b.origin == nil && captureStack.Load()) {
+		e.stack = callers(skip + 1 + b.callerSkip)
+	}
+	if o := b.origin; o != nil {
+		e.pc, e.source, e.time = o.pc, o.source, o.time
+		if !b.stack {
+			e.stack = o.stack
+		}
 	}
+	if b.origin != nil {
+		// The extended error already went through hooks and was reported
+		return e
+	}
+	e = runHooks(e)
+	if !b.unreported {
+		notify(e)
+		report(e)
+		recentErrors.record(e)
+	}
+	return e
 }
 
-// Error returns the Error as an error interface type
-func (b *Builder) Error() error {
-	return b.Build()
+// Unreported marks the error as already reported where it happened, such as
+// an error decoded from another service: Build still runs the build hooks but
+// skips observers, the reporter and the recent error buffer, so the failure
+// isn't counted twice
+// Decoders such as FromHTTPResponse and grpcx.FromStatus use it
+func (b *Builder) Unreported() *Builder {
+	b.unreported = true
+	return b
 }
 
 // WithDescription is a legacy method that immediately returns an Error
 // Consider using WithMessage().Build() instead for better fluency
 func (b *Builder) WithDescription(desc string) *Error {
 	b.message = desc
-	return b.Build()
+	b.template = desc
+	return b.build(1)
 }
 
 // WithDescriptionAndCause is a legacy method that immediately returns an Error
 // Consider using WithMessage().WithCause().Build() instead for better fluency
 func (b *Builder) WithDescriptionAndCause(desc string, cause error) *Error {
 	b.message = desc
+	b.template = desc
 	b.err = cause
-	return b.Build()
+	return b.build(1)
 }
 
 // Error constructors
@@ -152,6 +435,46 @@ func New(code Code) *Builder {
 	return &Builder{code: code}
 }
 
+// From creates a Builder seeded with everything recorded on err so callers
+// can add context without losing anything
+// The built error replaces err, keeping its cause, call site, stack and
+// creation time; as err already went through build hooks and was reported,
+// building it again skips them; other errors become the cause of a new error
+// with the same code as GetCode reports
+func From(err error) *Builder {
+	e, ok := err.(*Error)
+	if !ok {
+		if err == nil {
+			return &Builder{code: Internal}
+		}
+		return &Builder{code: GetCode(err), err: err}
+	}
+
+	return &Builder{
+		code:           e.Code,
+		message:        e.Message,
+		err:            e.Err,
+		op:             e.op,
+		namespace:      e.namespace,
+		fields:         maps.Clone(e.Fields),
+		severity:       e.severity,
+		retryable:      e.retryable,
+		retryAfter:     e.retryAfter,
+		template:       e.template,
+		templateParams: maps.Clone(e.templateParams),
+		fingerprint:    e.fingerprint,
+		fieldErrors:    slices.Clone(e.fieldErrors),
+		inlineCause:    e.inlineCause,
+		id:             e.id,
+		messageKey:     e.messageKey,
+		messageParams:  maps.Clone(e.messageParams),
+		details:        maps.Clone(e.details),
+		attachments:    maps.Clone(e.attachments),
+		challenges:     slices.Clone(e.challenges),
+		origin:         e,
+	}
+}
+
 // NewBadRequest creates an error builder for BadRequest errors
 func NewBadRequest() *Builder {
 	return &Builder{code: BadRequest}
@@ -197,22 +520,137 @@ func NewValidation() *Builder {
 	return &Builder{code: Validation}
 }
 
+// NewUnavailable creates an error builder for Unavailable errors
+func NewUnavailable() *Builder {
+	return &Builder{code: Unavailable}
+}
+
+// NewTooManyRequests creates an error builder for TooManyRequests errors
+func NewTooManyRequests() *Builder {
+	return &Builder{code: TooManyRequests}
+}
+
+// NewNotImplemented creates an error builder for NotImplemented errors
+func NewNotImplemented() *Builder {
+	return &Builder{code: NotImplemented}
+}
+
+// NewCancelled creates an error builder for Cancelled errors
+func NewCancelled() *Builder {
+	return &Builder{code: Cancelled}
+}
+
+// NewPreconditionFailed creates an error builder for PreconditionFailed errors
+func NewPreconditionFailed() *Builder {
+	return &Builder{code: PreconditionFailed}
+}
+
+// NewResourceExhausted creates an error builder for ResourceExhausted errors
+func NewResourceExhausted() *Builder {
+	return &Builder{code: ResourceExhausted}
+}
+
+// NewGone creates an error builder for Gone errors
+func NewGone() *Builder {
+	return &Builder{code: Gone}
+}
+
+// NewPayloadTooLarge creates an error builder for PayloadTooLarge errors
+func NewPayloadTooLarge() *Builder {
+	return &Builder{code: PayloadTooLarge}
+}
+
+// NewUnsupportedMediaType creates an error builder for UnsupportedMediaType errors
+func NewUnsupportedMediaType() *Builder {
+	return &Builder{code: UnsupportedMediaType}
+}
+
 // Wrap creates an Error that wraps an existing error with the given code
+// Wrapping a context error with Internal reports it as Cancelled or Timeout
+// instead, see FromContextErr
 func Wrap(err error, code Code, message string) *Error {
+	return wrap(1, err, code, message)
+}
+
+// WrapSkip is like Wrap but skips additional frames when recording the call
+// site, so helpers that wrap errors can report their own caller
+func WrapSkip(skip int, err error, code Code, message string) *Error {
+	return wrap(1+skip, err, code, message)
+}
+
+// WrapIfErr wraps an error only if it's not nil
+func WrapIfErr(err error, code Code, message string) error {
 	if err == nil {
 		return nil
 	}
-	return &Error{
-		Code:    code,
-		Message: message,
-		Err:     err,
+	return wrap(1, err, code, message)
+}
+
+// Wrapf is like Wrap but formats the message according to a format specifier
+func Wrapf(err error, code Code, format string, args ...any) *Error {
+	return wrapf(1, err, code, format, args...)
+}
+
+// WrapIfErrf is like WrapIfErr but formats the message according to a format
+// specifier, which is only done when err is not nil
+func WrapIfErrf(err error, code Code, format string, args ...any) error {
+	if err == nil {
+		return nil
 	}
+	return wrapf(1, err, code, format, args...)
 }
 
-// WrapIfErr wraps an error only if it's not nil
-func WrapIfErr(err error, code Code, message string) error {
+// DeferWrap wraps the error pointed to by errp if it's not nil
+// It's meant to be deferred so every return path of a function is wrapped the
+// same way: defer errx.DeferWrap(&err, errx.Internal, "saving user")
+func DeferWrap(errp *error, code Code, message string) {
+	if *errp != nil {
+		*errp = wrap(1, *errp, code, message)
+	}
+}
+
+// DeferWrapf is like DeferWrap but formats the message, which is only done
+// when there's an error to wrap
+func DeferWrapf(errp *error, code Code, format string, args ...any) {
+	if *errp != nil {
+		*errp = wrapf(1, *errp, code, format, args...)
+	}
+}
+
+// wrap creates the wrapping Error, skipping the given number of frames
+// above its caller when recording the call site
+func wrap(skip int, err error, code Code, message string) *Error {
 	if err == nil {
 		return nil
 	}
-	return Wrap(err, code, message)
+	return New(wrapCode(err, code)).WithMessage(message).WithCause(err).build(skip + 1)
+}
+
+// wrapf is like wrap but formats the message
+func wrapf(skip int, err error, code Code, format string, args ...any) *Error {
+	if err == nil {
+		return nil
+	}
+	return New(wrapCode(err, code)).WithMessagef(format, args...).WithCause(err).build(skip + 1)
+}
+
+// Errorf creates an Error with a message formatted like fmt.Errorf
+// A %w verb sets the cause, whose text stays part of the message exactly as
+// fmt.Errorf would render it; several %w verbs set a joined cause
+func Errorf(code Code, format string, args ...any) *Error {
+	formatted := fmt.Errorf(format, args...)
+
+	b := &Builder{
+		code:     code,
+		message:  formatted.Error(),
+		template: format,
+	}
+	switch u := formatted.(type) {
+	case interface{ Unwrap() error }:
+		b.err = u.Unwrap()
+	case interface{ Unwrap() []error }:
+		b.err = errors.Join(u.Unwrap()...)
+	}
+	b.inlineCause = b.err != nil
+	return b.build(1)
 }
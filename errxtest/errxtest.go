@@ -0,0 +1,138 @@
+// Package errxtest provides test assertions for errx errors
+// Assertions check codes, messages, fields and causes instead of formatted
+// strings and, on failure, report what was expected next to every layer of
+// the error chain
+//
+//	errxtest.AssertCode(t, err, errx.NotFound)
+//	errxtest.AssertField(t, err, "user_id", 42)
+package errxtest
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/nordew/go-errx"
+)
+
+// AssertCode reports a test error unless err matches code as errx.IsCode does
+// Returns whether the assertion passed
+func AssertCode(t testing.TB, err error, code errx.Code) bool {
+	t.Helper()
+	if err != nil && errx.IsCode(err, code) {
+		return true
+	}
+	t.Errorf("errxtest: error code mismatch\n  got:  %s\n  want: %s%s", gotCode(err), code, describe(err))
+	return false
+}
+
+// AssertMessageContains reports a test error unless the text of err, which
+// includes the messages of its causes, contains substr
+// Returns whether the assertion passed
+func AssertMessageContains(t testing.TB, err error, substr string) bool {
+	t.Helper()
+	if err != nil && strings.Contains(err.Error(), substr) {
+		return true
+	}
+	t.Errorf("errxtest: error message doesn't contain %q\n  text: %s%s", substr, text(err), describe(err))
+	return false
+}
+
+// AssertField reports a test error unless an Error in the chain has the field
+// key set to a value deeply equal to want
+// The outermost Error with the field is checked, so wrapping layers can
+// override inner values
+// Returns whether the assertion passed
+func AssertField(t testing.TB, err error, key string, want any) bool {
+	t.Helper()
+	got, ok := field(err, key)
+	switch {
+	case !ok:
+		t.Errorf("errxtest: error has no field %q\n  want: %s%s", key, value(want), describe(err))
+		return false
+	case !reflect.DeepEqual(got, want):
+		t.Errorf("errxtest: field %q mismatch\n  got:  %s\n  want: %s%s", key, value(got), value(want), describe(err))
+		return false
+	}
+	return true
+}
+
+// AssertWraps reports a test error unless errors.Is(err, target)
+// Returns whether the assertion passed
+func AssertWraps(t testing.TB, err, target error) bool {
+	t.Helper()
+	if errors.Is(err, target) {
+		return true
+	}
+	t.Errorf("errxtest: error doesn't wrap the target\n  target: %s%s", layer(target), describe(err))
+	return false
+}
+
+// field returns the value of key on the outermost Error in the chain having it
+func field(err error, key string) (any, bool) {
+	for cause := err; cause != nil; cause = errors.Unwrap(cause) {
+		if e, ok := cause.(*errx.Error); ok {
+			if v, ok := e.Fields[key]; ok {
+				return v, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// text returns the text of err for a mismatch message
+func text(err error) string {
+	if err == nil {
+		return "<nil error>"
+	}
+	return err.Error()
+}
+
+// gotCode describes the code of err for a mismatch message
+func gotCode(err error) string {
+	if err == nil {
+		return "<nil error>"
+	}
+	return string(errx.GetCode(err))
+}
+
+// describe lists the layers of the error chain, one per line
+func describe(err error) string {
+	if err == nil {
+		return "\n  error: <nil>"
+	}
+
+	var b strings.Builder
+	b.WriteString("\n  chain:")
+	for i, cause := 0, err; cause != nil; i, cause = i+1, errors.Unwrap(cause) {
+		fmt.Fprintf(&b, "\n    %d. %s", i, layer(cause))
+	}
+	return b.String()
+}
+
+// layer describes a single error without the text of its causes
+func layer(err error) string {
+	e, ok := err.(*errx.Error)
+	if !ok {
+		return fmt.Sprintf("%T: %s", err, err)
+	}
+
+	s := fmt.Sprintf("[%s] %s", e.Code, e.Message)
+	if len(e.Fields) > 0 {
+		s += fmt.Sprintf(" %v", e.Fields)
+	}
+	if src := e.Source(); src.File != "" {
+		s += " at " + src.String()
+	}
+	return s
+}
+
+// value formats a value with its type, so 42 and int64(42) are told apart
+func value(v any) string {
+	if v == nil {
+		return "<nil>"
+	}
+	return fmt.Sprintf("%#v (%T)", v, v)
+}
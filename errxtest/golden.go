@@ -0,0 +1,208 @@
+package errxtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/nordew/go-errx"
+)
+
+// UpdateEnv is the environment variable that, set to a true value, makes
+// AssertGolden rewrite golden files instead of comparing against them
+const UpdateEnv = "ERRX_UPDATE_GOLDEN"
+
+// Update makes AssertGolden rewrite golden files instead of comparing
+// against them; test packages can bind it to a flag of their own
+var Update bool
+
+// updating reports whether golden files should be rewritten: Update or
+// UpdateEnv is set, or the test package declared an -update flag that is set
+// No flag is registered here, so test packages are free to declare -update
+func updating() bool {
+	if Update {
+		return true
+	}
+	if update, err := strconv.ParseBool(os.Getenv(UpdateEnv)); err == nil && update {
+		return true
+	}
+	f := flag.Lookup("update")
+	if f == nil {
+		return false
+	}
+	getter, ok := f.Value.(flag.Getter)
+	if !ok {
+		return false
+	}
+	update, _ := getter.Get().(bool)
+	return update
+}
+
+// Serializer encodes an error in one of the formats exposed to clients
+type Serializer func(err error) ([]byte, error)
+
+// Serializers for the formats of the root package; grpcx.StatusJSON covers
+// gRPC statuses
+var (
+	JSON    Serializer = func(err error) ([]byte, error) { return json.Marshal(err) }
+	Wire    Serializer = errx.EncodeWire
+	Problem Serializer = func(err error) ([]byte, error) { return json.Marshal(errx.ToProblem(err)) }
+)
+
+// AssertGolden serializes err and compares the result with the golden file at
+// path, reporting a line diff on mismatch
+// Set Update or ERRX_UPDATE_GOLDEN, or run the tests with an -update flag the
+// test package declares, to write the file instead
+// The output is normalized before comparing: JSON is indented with sorted
+// keys, IDs and timestamps are replaced by placeholders and call sites keep
+// only the function and the file's base name
+// Returns whether the assertion passed
+func AssertGolden(t testing.TB, path string, err error, serialize Serializer) bool {
+	t.Helper()
+
+	data, serr := serialize(err)
+	if serr != nil {
+		t.Errorf("errxtest: serializing error: %v", serr)
+		return false
+	}
+	got, nerr := normalize(data)
+	if nerr != nil {
+		t.Errorf("errxtest: normalizing serialized error: %v", nerr)
+		return false
+	}
+
+	if updating() {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Errorf("errxtest: %v", err)
+			return false
+		}
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Errorf("errxtest: %v", err)
+			return false
+		}
+		return true
+	}
+
+	want, rerr := os.ReadFile(path)
+	if errors.Is(rerr, os.ErrNotExist) {
+		t.Errorf("errxtest: golden file %s doesn't exist; run the test with %s=1 to create it", path, UpdateEnv)
+		return false
+	}
+	if rerr != nil {
+		t.Errorf("errxtest: %v", rerr)
+		return false
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("errxtest: output differs from golden file %s (run with %s=1 to accept)\n%s", path, UpdateEnv, diff(string(want), string(got)))
+		return false
+	}
+	return true
+}
+
+// Placeholders replacing volatile values in golden output
+const (
+	idPlaceholder   = "<id>"
+	timePlaceholder = "<time>"
+	linePlaceholder = "<line>"
+)
+
+// normalize rewrites serialized JSON into the stable form stored in golden files
+func normalize(data []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+	enc := json.NewEncoder(&out)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(normalizeValue("", v)); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// normalizeValue replaces the volatile members of a decoded JSON value
+func normalizeValue(key string, v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		if key == "source" {
+			if file, ok := v["file"].(string); ok {
+				v["file"] = filepath.Base(file)
+			}
+			if _, ok := v["line"]; ok {
+				v["line"] = linePlaceholder
+			}
+			return v
+		}
+		for k, member := range v {
+			v[k] = normalizeValue(k, member)
+		}
+		return v
+	case []any:
+		for i, member := range v {
+			v[i] = normalizeValue(key, member)
+		}
+		return v
+	case string:
+		switch {
+		case v == "":
+			return v
+		case key == "id":
+			return idPlaceholder
+		case key == "time" || key == "timestamp":
+			return timePlaceholder
+		}
+	}
+	return v
+}
+
+// diff returns a line diff from want to got, marking removed lines with "-"
+// and added lines with "+"
+func diff(want, got string) string {
+	a := strings.Split(strings.TrimSuffix(want, "\n"), "\n")
+	b := strings.Split(strings.TrimSuffix(got, "\n"), "\n")
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var out strings.Builder
+	out.WriteString("--- want\n+++ got\n")
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			fmt.Fprintf(&out, "  %s\n", a[i])
+			i, j = i+1, j+1
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			fmt.Fprintf(&out, "- %s\n", a[i])
+			i++
+		default:
+			fmt.Fprintf(&out, "+ %s\n", b[j])
+			j++
+		}
+	}
+	return out.String()
+}
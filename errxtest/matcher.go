@@ -0,0 +1,88 @@
+package errxtest
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/nordew/go-errx"
+)
+
+// Matcher matches errors by code and optionally by message and fields, so
+// mocks can expect "any Conflict error" instead of a specific instance
+// It implements gomock.Matcher and gomock.GotFormatter; with testify pass its
+// Matches method to mock.MatchedBy:
+//
+//	reporter.EXPECT().Report(errxtest.CodeMatcher(errx.Conflict))
+//	reporter.On("Report", mock.MatchedBy(errxtest.CodeMatcher(errx.Conflict).Matches))
+type Matcher struct {
+	code    errx.Code
+	message string
+	fields  map[string]any
+}
+
+// CodeMatcher returns a Matcher accepting errors matching code as
+// errx.IsCode does
+func CodeMatcher(code errx.Code) *Matcher {
+	return &Matcher{code: code}
+}
+
+// WithMessage also requires the text of the error to contain substr
+func (m *Matcher) WithMessage(substr string) *Matcher {
+	m.message = substr
+	return m
+}
+
+// WithField also requires an Error in the chain to have the field key set to
+// a value deeply equal to value, as AssertField checks
+func (m *Matcher) WithField(key string, value any) *Matcher {
+	if m.fields == nil {
+		m.fields = make(map[string]any)
+	}
+	m.fields[key] = value
+	return m
+}
+
+// Matches reports whether x is an error satisfying the matcher
+func (m *Matcher) Matches(x any) bool {
+	err, ok := x.(error)
+	if !ok || !errx.IsCode(err, m.code) {
+		return false
+	}
+	if m.message != "" && !strings.Contains(err.Error(), m.message) {
+		return false
+	}
+	for key, want := range m.fields {
+		if got, ok := field(err, key); !ok || !reflect.DeepEqual(got, want) {
+			return false
+		}
+	}
+	return true
+}
+
+// String describes what the matcher accepts
+func (m *Matcher) String() string {
+	s := fmt.Sprintf("error with code %s", m.code)
+	if m.message != "" {
+		s += fmt.Sprintf(" and message containing %q", m.message)
+	}
+	keys := make([]string, 0, len(m.fields))
+	for key := range m.fields {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		s += fmt.Sprintf(" and field %s=%s", key, value(m.fields[key]))
+	}
+	return s
+}
+
+// Got describes a value that didn't match, listing the layers of errors
+func (m *Matcher) Got(got any) string {
+	err, ok := got.(error)
+	if !ok {
+		return value(got)
+	}
+	return text(err) + describe(err)
+}
@@ -74,8 +74,8 @@ func ProcessUserRequest(userID, input string) error {
 	repo := &UserRepository{}
 	userData, err := repo.GetUser(userID)
 	if err != nil {
-		// Here we could add more context if needed
-		return errx.WrapIfErr(err, errx.Internal, "failed during user processing")
+		// Add context without replacing the code set by the repository
+		return errx.From(err).WithOp("ProcessUserRequest").Error()
 	}
 
 	fmt.Println("Successfully processed:", userData)
@@ -84,46 +84,44 @@ func ProcessUserRequest(userID, input string) error {
 
 // HandleError demonstrates how to handle errors at API boundaries
 func HandleError(err error) {
-	if err == nil {
-		return
-	}
-
-	// Get the error code and message
+	// Get the error message
 	message := errx.GetMessage(err)
 
-	// Handle specific error cases
-	switch {
-	case errx.IsCode(err, errx.NotFound):
-		fmt.Printf("Resource not found: %s\n", message)
-		// HTTP equivalent: return 404 status code
-
-	case errx.IsCode(err, errx.BadRequest):
-		fmt.Printf("Bad request: %s\n", message)
-		// HTTP equivalent: return 400 status code
-
-	case errx.IsCode(err, errx.Validation):
-		fmt.Printf("Validation error: %s\n", message)
-		// HTTP equivalent: return 422 status code
+	// Handle specific error cases by code
+	errx.Match(err, errx.Handlers{
+		errx.NotFound: func(err error) {
+			fmt.Printf("Resource not found: %s\n", message)
+			// HTTP equivalent: return 404 status code
+		},
+		errx.BadRequest: func(err error) {
+			fmt.Printf("Bad request: %s\n", message)
+			// HTTP equivalent: return 400 status code
+		},
+		errx.Validation: func(err error) {
+			fmt.Printf("Validation error: %s\n", message)
+			// HTTP equivalent: return 422 status code
+		},
+		errx.Unauthorized: func(err error) {
+			fmt.Printf("Unauthorized: %s\n", message)
+			// HTTP equivalent: return 401 status code
+		},
+		errx.Forbidden: func(err error) {
+			fmt.Printf("Forbidden: %s\n", message)
+			// HTTP equivalent: return 403 status code
+		},
+	}, func(err error) {
+		if errors.Is(err, ErrUserNotFound) {
+			// Example of using errors.Is with custom errors
+			fmt.Println("User not found (using errors.Is)")
+			return
+		}
 
-	case errx.IsCode(err, errx.Unauthorized):
-		fmt.Printf("Unauthorized: %s\n", message)
-		// HTTP equivalent: return 401 status code
-
-	case errx.IsCode(err, errx.Forbidden):
-		fmt.Printf("Forbidden: %s\n", message)
-		// HTTP equivalent: return 403 status code
-
-	case errors.Is(err, ErrUserNotFound):
-		// Example of using errors.Is with custom errors
-		fmt.Println("User not found (using errors.Is)")
-
-	default:
 		// Handle internal errors or unexpected cases
 		fmt.Printf("Internal error: %s\n", err)
 		// Log the full error with its cause for debugging
 		log.Printf("ERROR: %+v\n", err)
 		// HTTP equivalent: return 500 status code
-	}
+	})
 }
 
 func main() {
@@ -0,0 +1,94 @@
+// Package fiberx provides a fiber.ErrorHandler for errx errors
+package fiberx
+
+import (
+	"log/slog"
+	"net/http"
+	"slices"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/nordew/go-errx"
+	"github.com/nordew/go-errx/httpx"
+)
+
+// Option configures the error handler
+type Option func(*options)
+
+type options struct {
+	logger   *slog.Logger
+	logCodes []errx.Code
+}
+
+// WithLogger sets the logger failed requests are logged to with
+// errx.LogContext, slog.Default() by default
+// If codes are given, errors with one of those codes are logged instead of
+// server errors (5xx)
+func WithLogger(logger *slog.Logger, codes ...errx.Code) Option {
+	return func(o *options) {
+		o.logger = logger
+		o.logCodes = codes
+	}
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{logger: slog.Default()}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// ErrorHandler returns a fiber.ErrorHandler that writes errors as JSON with
+// the status mapped from their code, using the same body and headers as
+// httpx.WriteError, including the message localized from Accept-Language
+// fiber.Error values are converted using their status and message; server
+// errors are logged while clients only see a generic message
+func ErrorHandler(opts ...Option) fiber.ErrorHandler {
+	o := newOptions(opts)
+	return func(c *fiber.Ctx, err error) error {
+		err = FromFiberError(err)
+		if o.shouldLog(errx.GetCode(err)) {
+			errx.LogContext(c.UserContext(), o.logger, err,
+				slog.String("method", c.Method()),
+				slog.String("path", c.Route().Path),
+			)
+		}
+
+		h := make(http.Header)
+		resp := httpx.Prepare(h, c.Get(fiber.HeaderAcceptLanguage), err)
+		for key, values := range h {
+			c.Response().Header.Del(key)
+			for _, value := range values {
+				c.Response().Header.Add(key, value)
+			}
+		}
+		return c.Status(errx.HTTPStatus(err)).JSON(resp)
+	}
+}
+
+// FromFiberError converts a fiber.Error into an Error with the code mapped
+// from its status and its message
+// Other errors are returned unchanged
+func FromFiberError(err error) error {
+	fe, ok := err.(*fiber.Error)
+	if !ok {
+		return err
+	}
+
+	code := errx.CodeFromHTTPStatus(fe.Code)
+	if code == "" {
+		code = errx.Internal
+	}
+	return errx.New(code).WithMessage(fe.Message).Build()
+}
+
+// shouldLog reports whether errors with the code are logged
+func (o *options) shouldLog(code errx.Code) bool {
+	if o.logger == nil {
+		return false
+	}
+	if len(o.logCodes) > 0 {
+		return slices.Contains(o.logCodes, code)
+	}
+	return errx.CodeHTTPStatus(code) >= 500
+}
@@ -0,0 +1,136 @@
+package fiberx
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/nordew/go-errx"
+	"github.com/nordew/go-errx/httpx"
+)
+
+// germanLocalizer translates every message to a fixed German text
+type germanLocalizer struct{}
+
+func (germanLocalizer) LocalizeAccept(err error, acceptLanguage string) (string, string) {
+	if acceptLanguage == "de" {
+		return "auf Deutsch", "de"
+	}
+	return errx.GetMessage(err), ""
+}
+
+// comparedHeaders are the headers both responses must agree on
+var comparedHeaders = []string{
+	errx.CodeHeader,
+	errx.IDHeader,
+	"Retry-After",
+	"X-RateLimit-Limit",
+	"X-RateLimit-Remaining",
+	"X-RateLimit-Reset",
+	"WWW-Authenticate",
+	"X-Content-Type-Options",
+	"Content-Language",
+	"Vary",
+	"Content-Type",
+}
+
+func TestErrorHandlerMatchesWriteError(t *testing.T) {
+	httpx.SetLocalizer(germanLocalizer{})
+	defer httpx.SetLocalizer(nil)
+
+	reset := time.Now().Add(time.Minute).Truncate(time.Second)
+	tests := []struct {
+		name   string
+		err    error
+		accept string
+	}{
+		{"not found", errx.New(errx.NotFound).WithMessage("user not found").WithID("id-1").WithField("user_id", 7).Error(), ""},
+		{"localized", errx.New(errx.Validation).WithMessage("invalid email").Error(), "de"},
+		{"rate limited", errx.NewRateLimited(100, 0, reset).WithMessage("slow down").Error(), ""},
+		{"challenge", errx.New(errx.Unauthorized).WithChallenge("Bearer", map[string]string{"realm": "api"}).Error(), ""},
+		{"unavailable", errx.New(errx.Unavailable).WithMessage("pool exhausted: db-7").WithRetryAfter(3 * time.Second).Error(), ""},
+		{"panic", errx.FromPanic("secret"), ""},
+		{"plain error", errors.New("dial tcp 10.0.0.5: refused"), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler(WithLogger(nil))})
+			app.Get("/", func(c *fiber.Ctx) error { return tt.err })
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept-Language", tt.accept)
+			}
+			got, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			want := httptest.NewRecorder()
+			httpx.WriteError(want, req, tt.err)
+
+			if got.StatusCode != want.Code {
+				t.Errorf("status = %d, want %d", got.StatusCode, want.Code)
+			}
+			for _, key := range comparedHeaders {
+				if g, w := got.Header.Values(key), want.Header().Values(key); !slices.Equal(g, w) {
+					t.Errorf("header %s = %q, want %q", key, g, w)
+				}
+			}
+
+			gotBody, _ := io.ReadAll(got.Body)
+			if g, w := decode(t, gotBody), decode(t, want.Body.Bytes()); !jsonEqual(g, w) {
+				t.Errorf("body = %s, want %s", gotBody, want.Body.Bytes())
+			}
+		})
+	}
+}
+
+func TestErrorHandlerMasksServerErrors(t *testing.T) {
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler(WithLogger(nil))})
+	app.Get("/", func(c *fiber.Ctx) error {
+		return errx.New(errx.Internal).WithMessage("query failed: password=hunter2").Error()
+	})
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	var got httpx.Response
+	if err := json.Unmarshal(body, &got); err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusInternalServerError || got.Message != http.StatusText(http.StatusInternalServerError) {
+		t.Errorf("response = %d %s, want a masked 500", resp.StatusCode, body)
+	}
+}
+
+func TestFromFiberError(t *testing.T) {
+	err := FromFiberError(fiber.NewError(fiber.StatusNotFound, "no route"))
+	if !errx.IsCode(err, errx.NotFound) || errx.GetMessage(err) != "no route" {
+		t.Errorf("FromFiberError = %v, want [NOT_FOUND] no route", err)
+	}
+}
+
+func decode(t *testing.T, data []byte) any {
+	t.Helper()
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		t.Fatalf("decoding %s: %v", data, err)
+	}
+	return v
+}
+
+func jsonEqual(a, b any) bool {
+	x, _ := json.Marshal(a)
+	y, _ := json.Marshal(b)
+	return string(x) == string(y)
+}
@@ -0,0 +1,53 @@
+package errx
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"reflect"
+)
+
+// fingerprintFrames is the number of top stack frames included in a fingerprint
+const fingerprintFrames = 3
+
+// Fingerprint returns a stable key for grouping occurrences of the same error
+// An explicit fingerprint set with WithFingerprint anywhere in the chain is
+// returned as is; otherwise the key is a hash of each layer's code and message
+// template (the format string, not the formatted arguments), the types of
+// wrapped errors that aren't Error values and the functions of the top stack
+// frames, so it doesn't change with IDs in messages or line numbers
+// Returns an empty string for a nil error
+func Fingerprint(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	for cause := err; cause != nil; cause = errors.Unwrap(cause) {
+		if e, ok := cause.(*Error); ok && e.fingerprint != "" {
+			return e.fingerprint
+		}
+	}
+
+	h := sha256.New()
+	for cause := err; cause != nil; cause = errors.Unwrap(cause) {
+		e, ok := cause.(*Error)
+		if !ok {
+			io.WriteString(h, reflect.TypeOf(cause).String())
+			h.Write([]byte{0})
+			continue
+		}
+
+		io.WriteString(h, string(e.Code))
+		h.Write([]byte{0})
+		io.WriteString(h, e.template)
+		h.Write([]byte{0})
+
+		frames := e.StackTrace()
+		for i := 0; i < len(frames) && i < fingerprintFrames; i++ {
+			io.WriteString(h, frames[i].Function)
+			h.Write([]byte{0})
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)[:16])
+}
@@ -0,0 +1,67 @@
+package errx
+
+import (
+	"fmt"
+	"io"
+)
+
+// Format implements fmt.Formatter
+// %s and %v print the compact form returned by Error, %q prints it quoted,
+// and %+v prints the code and message of every layer in the cause chain
+// along with its captured stack frames or call site, unless another
+// formatter was set with WithFormatter
+func (e *Error) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			if fn := formatter.Load(); fn != nil {
+				(*fn)(s, e)
+				return
+			}
+			e.writeVerbose(s)
+			return
+		}
+		io.WriteString(s, e.Error())
+	case 's':
+		io.WriteString(s, e.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", e.Error())
+	default:
+		fmt.Fprintf(s, "%%!%c(*errx.Error=%s)", verb, e.Error())
+	}
+}
+
+// writeVerbose writes the multi-line representation used by %+v, which
+// includes fields in development mode
+func (e *Error) writeVerbose(w io.Writer) {
+	current := e
+	for {
+		if current.op != "" {
+			fmt.Fprintf(w, "[%s] %s: %s", current.Code, current.op, current.Message)
+		} else {
+			fmt.Fprintf(w, "[%s] %s", current.Code, current.Message)
+		}
+		if CurrentMode() == ModeDevelopment {
+			writeFields(w, current.Fields)
+		}
+		if frames := current.StackTrace(); len(frames) > 0 {
+			for _, frame := range frames {
+				fmt.Fprintf(w, "\n\t%s\n\t\t%s:%d", frame.Function, frame.File, frame.Line)
+			}
+		} else if src := current.Source(); src.File != "" {
+			fmt.Fprintf(w, "\n\t%s\n\t\t%s:%d", src.Function, src.File, src.Line)
+		}
+
+		if current.Err == nil {
+			return
+		}
+		io.WriteString(w, "\ncaused by: ")
+
+		next, ok := current.Err.(*Error)
+		if !ok {
+			io.WriteString(w, current.Err.Error())
+			return
+		}
+		current = next
+	}
+}
@@ -0,0 +1,68 @@
+package errx
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// recordReports counts the errors observed and reported until the returned
+// function is called
+func recordReports(t *testing.T) (observed, reported *int) {
+	t.Helper()
+	observed, reported = new(int), new(int)
+	remove := Observe(func(*Error) { *observed++ })
+	SetReporter(ReporterFunc(func(*Error) { *reported++ }))
+	t.Cleanup(func() {
+		remove()
+		SetReporter(nil)
+	})
+	return observed, reported
+}
+
+func TestFromDoesNotReportAgain(t *testing.T) {
+	observed, reported := recordReports(t)
+
+	err := New(Internal).WithMessage("query failed").WithID("id-1").Build()
+	extended := From(err).WithField("user_id", 7).Build()
+
+	if *observed != 1 || *reported != 1 {
+		t.Errorf("observed %d and reported %d times, want once", *observed, *reported)
+	}
+	if extended.ID() != "id-1" || extended.Fields["user_id"] != 7 {
+		t.Errorf("extended error = %+v", extended)
+	}
+}
+
+func TestFromPlainErrorIsReported(t *testing.T) {
+	_, reported := recordReports(t)
+
+	From(io.ErrUnexpectedEOF).WithMessage("reading body").Build()
+	if *reported != 1 {
+		t.Errorf("reported %d times, want once", *reported)
+	}
+}
+
+func TestUnreported(t *testing.T) {
+	observed, reported := recordReports(t)
+
+	hooked := 0
+	remove := OnBuild(func(e *Error) *Error { hooked++; return e })
+	defer remove()
+
+	New(Internal).Unreported().Build()
+	resp := &http.Response{
+		StatusCode: http.StatusBadGateway,
+		Header:     http.Header{},
+		Body:       io.NopCloser(strings.NewReader("")),
+	}
+	FromHTTPResponse(resp)
+
+	if *observed != 0 || *reported != 0 {
+		t.Errorf("observed %d and reported %d times, want never", *observed, *reported)
+	}
+	if hooked != 2 {
+		t.Errorf("build hooks ran %d times, want 2", hooked)
+	}
+}
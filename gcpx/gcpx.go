@@ -0,0 +1,99 @@
+// Package gcpx maps Google Cloud API errors to errx errors
+package gcpx
+
+import (
+	"errors"
+
+	"github.com/googleapis/gax-go/v2/apierror"
+	"github.com/nordew/go-errx"
+	"github.com/nordew/go-errx/grpcx"
+)
+
+// Field keys used for the details of a Google Cloud error
+const (
+	ReasonField   = "gcp_reason"
+	DomainField   = "gcp_domain"
+	MetadataField = "gcp_metadata"
+)
+
+// MapError wraps a Google Cloud API error in an Error with a code derived from
+// its canonical gRPC code or HTTP status
+// Both googleapi.Error values from REST clients and gRPC statuses from gRPC
+// clients are recognized; the error reason, domain and metadata are recorded
+// as fields, resource info as the resource, field violations as field errors
+// and retry info as the retry delay
+// Errors that already carry an errx code are returned unchanged
+// Returns nil for a nil error
+func MapError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var e *errx.Error
+	if errors.As(err, &e) {
+		return err
+	}
+
+	apiErr, ok := parse(err)
+	if !ok {
+		return errx.WrapSkip(1, err, errx.Internal, "")
+	}
+
+	b := errx.New(code(apiErr)).WithCause(err).WithCallerSkip(1)
+	if reason := apiErr.Reason(); reason != "" {
+		b.WithField(ReasonField, reason)
+	}
+	if domain := apiErr.Domain(); domain != "" {
+		b.WithField(DomainField, domain)
+	}
+	if metadata := apiErr.Metadata(); len(metadata) > 0 {
+		b.WithField(MetadataField, metadata)
+	}
+
+	details := apiErr.Details()
+	if ri := details.ResourceInfo; ri != nil {
+		b.WithResource(ri.GetResourceType(), ri.GetResourceName())
+	}
+	if br := details.BadRequest; br != nil {
+		for _, v := range br.GetFieldViolations() {
+			b.WithFieldError(v.GetField(), v.GetReason(), v.GetDescription())
+		}
+	}
+	if ri := details.RetryInfo; ri != nil && ri.GetRetryDelay() != nil {
+		b.WithRetryAfter(ri.GetRetryDelay().AsDuration())
+	}
+	return b.Build()
+}
+
+// Code returns the errx code describing a Google Cloud API error
+// Returns Internal if err isn't a Google Cloud API error
+func Code(err error) errx.Code {
+	apiErr, ok := parse(err)
+	if !ok {
+		return errx.Internal
+	}
+	return code(apiErr)
+}
+
+// parse returns the APIError describing err
+func parse(err error) (*apierror.APIError, bool) {
+	var apiErr *apierror.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr, true
+	}
+	return apierror.ParseError(err, false)
+}
+
+// code maps the HTTP status of an APIError from a REST client, or the
+// canonical gRPC code otherwise
+func code(apiErr *apierror.APIError) errx.Code {
+	if status := apiErr.HTTPCode(); status > 0 {
+		if code := errx.CodeFromHTTPStatus(status); code != "" {
+			return code
+		}
+	}
+	if st := apiErr.GRPCStatus(); st != nil {
+		return grpcx.CodeFromGRPC(st.Code())
+	}
+	return errx.Internal
+}
@@ -0,0 +1,24 @@
+module github.com/nordew/go-errx/gcpx
+
+go 1.24.1
+
+require (
+	github.com/googleapis/gax-go/v2 v2.14.2
+	github.com/nordew/go-errx v0.0.0
+	github.com/nordew/go-errx/grpcx v0.0.0
+)
+
+require (
+	golang.org/x/net v0.40.0 // indirect
+	golang.org/x/sys v0.33.0 // indirect
+	golang.org/x/text v0.25.0 // indirect
+	google.golang.org/api v0.232.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20250528174236-200df99c418a // indirect
+	google.golang.org/grpc v1.74.2 // indirect
+	google.golang.org/protobuf v1.36.6 // indirect
+)
+
+replace (
+	github.com/nordew/go-errx => ../
+	github.com/nordew/go-errx/grpcx => ../grpcx
+)
@@ -0,0 +1,85 @@
+// Package ginx writes errx errors from gin handlers as JSON responses
+package ginx
+
+import (
+	"log/slog"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nordew/go-errx"
+	"github.com/nordew/go-errx/httpx"
+)
+
+// Option configures the middleware
+type Option func(*options)
+
+type options struct {
+	logger   *slog.Logger
+	logCodes []errx.Code
+}
+
+// WithLogger logs errors before they are written, with errx.LogContext
+// If codes are given, only errors with one of those codes are logged
+func WithLogger(logger *slog.Logger, codes ...errx.Code) Option {
+	return func(o *options) {
+		o.logger = logger
+		o.logCodes = codes
+	}
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// Middleware writes the last error pushed with c.Error as a JSON response
+// with the status mapped from its code, using the same body as httpx.WriteError,
+// and recovers panics into Internal errors
+// Nothing is written if the handler already wrote a response
+func Middleware(opts ...Option) gin.HandlerFunc {
+	o := newOptions(opts)
+	return func(c *gin.Context) {
+		defer func() {
+			if r := recover(); r != nil {
+				c.Error(errx.FromPanic(r))
+				o.write(c)
+			}
+		}()
+
+		c.Next()
+		o.write(c)
+	}
+}
+
+// Handle adapts a handler returning an error into a gin.HandlerFunc that
+// pushes the error with c.Error and aborts the chain
+func Handle(fn func(c *gin.Context) error) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := fn(c); err != nil {
+			c.Error(err)
+			c.Abort()
+		}
+	}
+}
+
+// write logs and writes the last error of the context, if any
+func (o *options) write(c *gin.Context) {
+	last := c.Errors.Last()
+	if last == nil || c.Writer.Written() {
+		return
+	}
+
+	err := last.Err
+	if o.logger != nil && (len(o.logCodes) == 0 || slices.Contains(o.logCodes, errx.GetCode(err))) {
+		errx.LogContext(c.Request.Context(), o.logger, err,
+			slog.String("method", c.Request.Method),
+			slog.String("path", c.FullPath()),
+		)
+	}
+
+	httpx.WriteError(c.Writer, c.Request, err)
+	c.Abort()
+}
@@ -0,0 +1,119 @@
+package ginx
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nordew/go-errx"
+	"github.com/nordew/go-errx/httpx"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+func serve(t *testing.T, opts []Option, handlers ...gin.HandlerFunc) *httptest.ResponseRecorder {
+	t.Helper()
+	r := gin.New()
+	r.Use(Middleware(opts...))
+	r.GET("/users/:id", handlers...)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/7", nil))
+	return rec
+}
+
+func decode(t *testing.T, rec *httptest.ResponseRecorder) httpx.Response {
+	t.Helper()
+	var resp httpx.Response
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decoding %s: %v", rec.Body.Bytes(), err)
+	}
+	return resp
+}
+
+func TestMiddleware(t *testing.T) {
+	rec := serve(t, nil, Handle(func(c *gin.Context) error {
+		return errx.NewNotFound().WithMessage("user not found").WithField("user_id", 7).
+			WithRetryAfter(2 * time.Second).Error()
+	}))
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+	if got := rec.Header().Get(errx.CodeHeader); got != string(errx.NotFound) {
+		t.Errorf("%s = %q, want %q", errx.CodeHeader, got, errx.NotFound)
+	}
+	if got := rec.Header().Get("Retry-After"); got != "2" {
+		t.Errorf("Retry-After = %q, want 2", got)
+	}
+	resp := decode(t, rec)
+	if resp.Code != errx.NotFound || resp.Message != "user not found" || resp.Fields["user_id"] != float64(7) {
+		t.Errorf("body = %s, want the not found error", rec.Body.Bytes())
+	}
+}
+
+func TestMiddlewareMasksServerErrors(t *testing.T) {
+	rec := serve(t, nil, Handle(func(c *gin.Context) error {
+		return errx.NewInternal().WithMessage("query failed: password=hunter2").WithField("dsn", "db-7").Error()
+	}))
+
+	resp := decode(t, rec)
+	if rec.Code != http.StatusInternalServerError || resp.Message != http.StatusText(http.StatusInternalServerError) ||
+		len(resp.Fields) != 0 {
+		t.Errorf("response = %d %s, want a masked 500", rec.Code, rec.Body.Bytes())
+	}
+}
+
+func TestMiddlewareRecoversPanics(t *testing.T) {
+	rec := serve(t, nil, func(c *gin.Context) { panic("secret") })
+
+	if rec.Code != http.StatusInternalServerError || strings.Contains(rec.Body.String(), "secret") {
+		t.Errorf("response = %d %s, want a masked 500", rec.Code, rec.Body.Bytes())
+	}
+}
+
+func TestMiddlewareKeepsWrittenResponses(t *testing.T) {
+	rec := serve(t, nil, func(c *gin.Context) {
+		c.String(http.StatusAccepted, "queued")
+		c.Error(errx.NewInternal().Error())
+	})
+
+	if rec.Code != http.StatusAccepted || rec.Body.String() != "queued" {
+		t.Errorf("response = %d %s, want the handler's 202", rec.Code, rec.Body.Bytes())
+	}
+}
+
+func TestHandleAbortsChain(t *testing.T) {
+	called := false
+	serve(t, nil,
+		Handle(func(c *gin.Context) error { return errx.NewBadRequest().Error() }),
+		func(c *gin.Context) { called = true },
+	)
+	if called {
+		t.Error("handler after a failing Handle was called")
+	}
+}
+
+func TestWithLoggerCodes(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	opts := []Option{WithLogger(logger, errx.Internal)}
+
+	serve(t, opts, Handle(func(c *gin.Context) error { return errx.NewNotFound().Error() }))
+	if buf.Len() != 0 {
+		t.Errorf("logged %s for a code not in the filter", buf.Bytes())
+	}
+
+	serve(t, opts, Handle(func(c *gin.Context) error { return errx.NewInternal().Error() }))
+	if !strings.Contains(buf.String(), `"path":"/users/:id"`) {
+		t.Errorf("log = %s, want the route path", buf.Bytes())
+	}
+}
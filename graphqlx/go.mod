@@ -0,0 +1,9 @@
+module github.com/nordew/go-errx/graphqlx
+
+go 1.24.1
+
+require github.com/nordew/go-errx v0.0.0
+
+require github.com/graphql-go/graphql v0.8.1
+
+replace github.com/nordew/go-errx => ../
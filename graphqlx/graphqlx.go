@@ -0,0 +1,87 @@
+// Package graphqlx formats errx errors for graphql-go/graphql responses
+package graphqlx
+
+import (
+	"errors"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/nordew/go-errx"
+	"github.com/nordew/go-errx/httpx"
+)
+
+// FormatError formats an error returned by a resolver
+// Errors carrying an errx code get the public message of httpx.NewResponse
+// and extensions holding the code, numeric code, ID, fields and field errors;
+// other errors, such as query validation errors, are formatted as graphql-go
+// would
+func FormatError(err error) gqlerrors.FormattedError {
+	formatted := gqlerrors.FormatError(err)
+
+	var e *errx.Error
+	if !errors.As(original(err), &e) {
+		return formatted
+	}
+
+	resp := httpx.NewResponse(e)
+	formatted.Message = resp.Message
+	formatted.Extensions = Extensions(e)
+	return formatted
+}
+
+// FormatErrors formats every error of a graphql-go result with FormatError
+func FormatErrors(errs []gqlerrors.FormattedError) []gqlerrors.FormattedError {
+	formatted := make([]gqlerrors.FormattedError, len(errs))
+	for i, err := range errs {
+		formatted[i] = FormatError(err)
+	}
+	return formatted
+}
+
+// FormatResult formats the errors of a result in place and returns it
+func FormatResult(result *graphql.Result) *graphql.Result {
+	if result != nil && len(result.Errors) > 0 {
+		result.Errors = FormatErrors(result.Errors)
+	}
+	return result
+}
+
+// Extensions returns the GraphQL error extensions describing err
+func Extensions(err error) map[string]any {
+	resp := httpx.NewResponse(err)
+	ext := map[string]any{
+		"code":         resp.Code,
+		"numeric_code": resp.NumericCode,
+	}
+	if resp.ID != "" {
+		ext["id"] = resp.ID
+	}
+	if len(resp.Fields) > 0 {
+		ext["fields"] = resp.Fields
+	}
+	if len(resp.FieldErrors) > 0 {
+		ext["field_errors"] = resp.FieldErrors
+	}
+	return ext
+}
+
+// original returns the error a resolver returned, unwrapping the error types
+// graphql-go wraps it in
+func original(err error) error {
+	for {
+		switch e := err.(type) {
+		case gqlerrors.FormattedError:
+			if e.OriginalError() == nil {
+				return err
+			}
+			err = e.OriginalError()
+		case *gqlerrors.Error:
+			if e.OriginalError == nil {
+				return err
+			}
+			err = e.OriginalError
+		default:
+			return err
+		}
+	}
+}
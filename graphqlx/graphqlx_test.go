@@ -0,0 +1,103 @@
+package graphqlx
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/nordew/go-errx"
+)
+
+// query runs a query against a schema whose user field fails with err
+func query(t *testing.T, err error, q string) *graphql.Result {
+	t.Helper()
+	schema, serr := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"user": &graphql.Field{
+					Type: graphql.String,
+					Resolve: func(p graphql.ResolveParams) (any, error) {
+						return nil, err
+					},
+				},
+			},
+		}),
+	})
+	if serr != nil {
+		t.Fatal(serr)
+	}
+	return FormatResult(graphql.Do(graphql.Params{Schema: schema, RequestString: q}))
+}
+
+func TestFormatResult(t *testing.T) {
+	err := errx.New(errx.Validation).
+		WithMessage("invalid user").
+		WithID("id-1").
+		WithField("user_id", 7).
+		WithFieldError("email", "format", "must be an email").
+		Error()
+
+	result := query(t, err, "{ user }")
+	if len(result.Errors) != 1 {
+		t.Fatalf("errors = %v, want one", result.Errors)
+	}
+	got := result.Errors[0]
+	if got.Message != "invalid user" {
+		t.Errorf("message = %q, want %q", got.Message, "invalid user")
+	}
+	ext := got.Extensions
+	if ext["code"] != errx.Validation || ext["numeric_code"] != errx.NumericCode(err) || ext["id"] != "id-1" {
+		t.Errorf("extensions = %v, want the code, numeric code and ID", ext)
+	}
+	if fields, _ := ext["fields"].(map[string]any); fields["user_id"] != 7 {
+		t.Errorf("fields = %v, want user_id 7", ext["fields"])
+	}
+	if ext["field_errors"] == nil {
+		t.Errorf("extensions = %v, want field errors", ext)
+	}
+	if len(got.Path) != 1 || got.Path[0] != "user" {
+		t.Errorf("path = %v, want [user]", got.Path)
+	}
+}
+
+func TestFormatResultMasksServerErrors(t *testing.T) {
+	for name, err := range map[string]error{
+		"internal": errx.New(errx.Internal).WithMessage("query failed: password=hunter2").WithField("dsn", "db-7").Error(),
+		"panic":    errx.FromPanic("secret"),
+	} {
+		t.Run(name, func(t *testing.T) {
+			got := query(t, err, "{ user }").Errors[0]
+			if got.Message != http.StatusText(http.StatusInternalServerError) {
+				t.Errorf("message = %q, want %q", got.Message, http.StatusText(http.StatusInternalServerError))
+			}
+			if _, ok := got.Extensions["fields"]; ok {
+				t.Errorf("extensions = %v, want no fields", got.Extensions)
+			}
+			if got.Extensions["code"] != errx.GetCode(err) {
+				t.Errorf("code = %v, want %s", got.Extensions["code"], errx.GetCode(err))
+			}
+		})
+	}
+}
+
+func TestFormatResultOtherErrors(t *testing.T) {
+	// Resolver errors without an errx code
+	got := query(t, errors.New("boom"), "{ user }").Errors[0]
+	if got.Message != "boom" || got.Extensions != nil {
+		t.Errorf("resolver error = %q %v, want it formatted as graphql-go does", got.Message, got.Extensions)
+	}
+
+	// Query validation errors
+	result := query(t, nil, "{ missing }")
+	if len(result.Errors) != 1 || result.Errors[0].Extensions != nil {
+		t.Errorf("validation errors = %v, want them formatted as graphql-go does", result.Errors)
+	}
+}
+
+func TestFormatResultNil(t *testing.T) {
+	if FormatResult(nil) != nil {
+		t.Error("FormatResult(nil) != nil")
+	}
+}
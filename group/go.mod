@@ -0,0 +1,10 @@
+module github.com/nordew/go-errx/group
+
+go 1.24.1
+
+require (
+	github.com/nordew/go-errx v0.0.0
+	golang.org/x/sync v0.16.0
+)
+
+replace github.com/nordew/go-errx => ../
@@ -0,0 +1,151 @@
+// Package group runs goroutines with errgroup and returns their errors as
+// errx errors, wrapped with the name of the failed task
+//
+//	g, ctx := group.WithContext(ctx)
+//	g.Go("load user", func() error { return loadUser(ctx, id) })
+//	g.Go("load orders", func() error { return loadOrders(ctx, id) })
+//	err := g.Wait() // [NOT_FOUND] load user: user not found
+package group
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/nordew/go-errx"
+	"golang.org/x/sync/errgroup"
+)
+
+// TaskField is the field key holding the name of the failed task
+const TaskField = "task"
+
+// Option configures a Group
+type Option func(*Group)
+
+// WithCode sets the code of task errors that aren't errx errors, Internal by
+// default
+func WithCode(code errx.Code) Option {
+	return func(g *Group) {
+		g.code = code
+	}
+}
+
+// CollectAll makes Wait return the errors of every failed task joined with
+// errx.Join instead of only the first one; with WithContext the context is
+// still cancelled by the first failure
+func CollectAll() Option {
+	return func(g *Group) {
+		g.all = true
+	}
+}
+
+// Group is a collection of goroutines working on subtasks of the same task,
+// like errgroup.Group
+type Group struct {
+	g    *errgroup.Group
+	code errx.Code
+	all  bool
+
+	mu   sync.Mutex
+	errs []error
+}
+
+// New creates a Group whose failures don't cancel the other tasks
+func New(opts ...Option) *Group {
+	return newGroup(&errgroup.Group{}, opts)
+}
+
+// WithContext creates a Group and a context derived from ctx that is
+// cancelled when a task fails or Wait returns
+func WithContext(ctx context.Context, opts ...Option) (*Group, context.Context) {
+	g, ctx := errgroup.WithContext(ctx)
+	return newGroup(g, opts), ctx
+}
+
+func newGroup(g *errgroup.Group, opts []Option) *Group {
+	group := &Group{g: g, code: errx.Internal}
+	for _, opt := range opts {
+		opt(group)
+	}
+	return group
+}
+
+// SetLimit limits the number of tasks running at once, as
+// errgroup.Group.SetLimit does
+func (g *Group) SetLimit(n int) {
+	g.g.SetLimit(n)
+}
+
+// Go runs fn in a new goroutine as the task named name
+// A returned error is wrapped with the name as its message and operation and
+// the TaskField field; it keeps its code if it's an errx error, context
+// cancellations become Cancelled and deadlines Timeout, and other errors get
+// the group's code
+func (g *Group) Go(name string, fn func() error) {
+	g.g.Go(func() error {
+		return g.wrap(name, fn())
+	})
+}
+
+// TryGo runs fn like Go if the number of running tasks is below the limit
+// set with SetLimit, reporting whether it was started
+func (g *Group) TryGo(name string, fn func() error) bool {
+	return g.g.TryGo(func() error {
+		return g.wrap(name, fn())
+	})
+}
+
+// Wait blocks until every task has returned, then returns the first task
+// error, or all of them joined with CollectAll
+// Joined errors leave out the cancellations of tasks stopped by an earlier
+// failure; Wait returns nil if no task failed
+func (g *Group) Wait() error {
+	first := g.g.Wait()
+	if !g.all || first == nil {
+		return first
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	var failures []error
+	for _, err := range g.errs {
+		if !errx.IsCode(err, errx.Cancelled) {
+			failures = append(failures, err)
+		}
+	}
+	if len(failures) == 0 {
+		return errx.Join(g.errs...)
+	}
+	return errx.Join(failures...)
+}
+
+// wrap converts the error of a task and records it for CollectAll
+func (g *Group) wrap(name string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	code := g.code
+	var e *errx.Error
+	switch {
+	case errors.Is(err, context.Canceled):
+		code = errx.Cancelled
+	case errors.Is(err, context.DeadlineExceeded):
+		code = errx.Timeout
+	case errors.As(err, &e):
+		code = e.Code
+	}
+	wrapped := errx.New(code).
+		WithMessage(name).
+		WithOp(name).
+		WithField(TaskField, name).
+		WithCause(err).
+		Build()
+
+	if g.all {
+		g.mu.Lock()
+		g.errs = append(g.errs, wrapped)
+		g.mu.Unlock()
+	}
+	return wrapped
+}
@@ -0,0 +1,67 @@
+package grpcx
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/nordew/go-errx"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryClientInterceptor converts status errors returned by upstream unary
+// calls into errx errors
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return FromError(invoker(ctx, method, req, reply, cc, opts...))
+	}
+}
+
+// StreamClientInterceptor converts status errors returned by upstream streams
+// into errx errors
+func StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		cs, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			return nil, FromError(err)
+		}
+		return &clientStream{ClientStream: cs}, nil
+	}
+}
+
+// FromError converts an error carrying a gRPC status into an Error
+// Returns err unchanged if it is nil, io.EOF, already an Error or not a status error
+func FromError(err error) error {
+	if err == nil || errors.Is(err, io.EOF) {
+		return err
+	}
+
+	var e *errx.Error
+	if errors.As(err, &e) {
+		return err
+	}
+
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+	return FromStatus(st)
+}
+
+// clientStream converts errors from the wrapped stream into errx errors
+type clientStream struct {
+	grpc.ClientStream
+}
+
+func (s *clientStream) SendMsg(m any) error {
+	return FromError(s.ClientStream.SendMsg(m))
+}
+
+func (s *clientStream) RecvMsg(m any) error {
+	return FromError(s.ClientStream.RecvMsg(m))
+}
+
+func (s *clientStream) CloseSend() error {
+	return FromError(s.ClientStream.CloseSend())
+}
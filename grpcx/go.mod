@@ -0,0 +1,18 @@
+module github.com/nordew/go-errx/grpcx
+
+go 1.24.1
+
+require (
+	github.com/nordew/go-errx v0.0.0
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20250528174236-200df99c418a
+	google.golang.org/grpc v1.74.2
+	google.golang.org/protobuf v1.36.6
+)
+
+require (
+	golang.org/x/net v0.40.0 // indirect
+	golang.org/x/sys v0.33.0 // indirect
+	golang.org/x/text v0.25.0 // indirect
+)
+
+replace github.com/nordew/go-errx => ../
@@ -0,0 +1,78 @@
+package grpcx
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+
+	"github.com/nordew/go-errx"
+	"google.golang.org/grpc"
+)
+
+// Option configures the server interceptors
+type Option func(*options)
+
+type options struct {
+	logger   *slog.Logger
+	logCodes []errx.Code
+}
+
+// WithLogger logs errors returned by handlers with errx.LogContext before
+// they are converted
+// If codes are given, only errors with one of those codes are logged
+func WithLogger(logger *slog.Logger, codes ...errx.Code) Option {
+	return func(o *options) {
+		o.logger = logger
+		o.logCodes = codes
+	}
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// UnaryServerInterceptor converts errors returned by unary handlers into gRPC
+// statuses and recovers panics into Internal errors
+func UnaryServerInterceptor(opts ...Option) grpc.UnaryServerInterceptor {
+	o := newOptions(opts)
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = errx.FromPanic(r)
+			}
+			err = o.convert(ctx, info.FullMethod, err)
+		}()
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor converts errors returned by stream handlers into gRPC
+// statuses and recovers panics into Internal errors
+func StreamServerInterceptor(opts ...Option) grpc.StreamServerInterceptor {
+	o := newOptions(opts)
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = errx.FromPanic(r)
+			}
+			err = o.convert(ss.Context(), info.FullMethod, err)
+		}()
+		return handler(srv, ss)
+	}
+}
+
+// convert logs the error if configured and converts it into a status error
+func (o *options) convert(ctx context.Context, method string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if o.logger != nil && (len(o.logCodes) == 0 || slices.Contains(o.logCodes, errx.GetCode(err))) {
+		errx.LogContext(ctx, o.logger, err, slog.String("method", method))
+	}
+	return ToStatus(err).Err()
+}
@@ -0,0 +1,96 @@
+package grpcx
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/nordew/go-errx"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var unaryInfo = &grpc.UnaryServerInfo{FullMethod: "/users.Users/Get"}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	interceptor := UnaryServerInterceptor()
+	_, err := interceptor(context.Background(), nil, unaryInfo, func(ctx context.Context, req any) (any, error) {
+		return nil, errx.NewNotFound().WithMessage("user not found").Error()
+	})
+
+	st, _ := status.FromError(err)
+	if st.Code() != codes.NotFound || st.Message() != "user not found" {
+		t.Errorf("status = %s %q, want NotFound %q", st.Code(), st.Message(), "user not found")
+	}
+
+	resp, err := interceptor(context.Background(), nil, unaryInfo, func(ctx context.Context, req any) (any, error) {
+		return "ok", nil
+	})
+	if resp != "ok" || err != nil {
+		t.Errorf("interceptor = %v, %v, want the handler's response", resp, err)
+	}
+}
+
+func TestUnaryServerInterceptorRecoversPanics(t *testing.T) {
+	_, err := UnaryServerInterceptor()(context.Background(), nil, unaryInfo, func(ctx context.Context, req any) (any, error) {
+		panic("nil map write in billing")
+	})
+
+	st, _ := status.FromError(err)
+	if st.Code() != codes.Internal || strings.Contains(st.Message(), "billing") {
+		t.Errorf("status = %s %q, want a masked Internal status", st.Code(), st.Message())
+	}
+	if back, want := FromStatus(st).Code, errx.GetCode(errx.FromPanic("x")); back != want {
+		t.Errorf("FromStatus code = %s, want %s", back, want)
+	}
+}
+
+// serverStream is a grpc.ServerStream with a context
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s serverStream) Context() context.Context { return s.ctx }
+
+func TestStreamServerInterceptor(t *testing.T) {
+	ss := serverStream{ctx: context.Background()}
+	info := &grpc.StreamServerInfo{FullMethod: "/users.Users/List"}
+
+	err := StreamServerInterceptor()(nil, ss, info, func(srv any, stream grpc.ServerStream) error {
+		return errx.NewInternal().WithMessage("query failed: password=hunter2").Error()
+	})
+	st, _ := status.FromError(err)
+	if st.Code() != codes.Internal || strings.Contains(st.Message(), "hunter2") {
+		t.Errorf("status = %s %q, want a masked Internal status", st.Code(), st.Message())
+	}
+
+	err = StreamServerInterceptor()(nil, ss, info, func(srv any, stream grpc.ServerStream) error {
+		panic("boom")
+	})
+	if st, _ := status.FromError(err); st.Code() != codes.Internal {
+		t.Errorf("status = %s, want Internal for a panic", st.Code())
+	}
+}
+
+func TestWithLoggerCodes(t *testing.T) {
+	var buf bytes.Buffer
+	interceptor := UnaryServerInterceptor(WithLogger(slog.New(slog.NewJSONHandler(&buf, nil)), errx.Internal))
+
+	interceptor(context.Background(), nil, unaryInfo, func(ctx context.Context, req any) (any, error) {
+		return nil, errx.NewNotFound().Error()
+	})
+	if buf.Len() != 0 {
+		t.Errorf("logged %s for a code not in the filter", buf.Bytes())
+	}
+
+	interceptor(context.Background(), nil, unaryInfo, func(ctx context.Context, req any) (any, error) {
+		return nil, errx.NewInternal().Error()
+	})
+	if !strings.Contains(buf.String(), `"method":"/users.Users/Get"`) {
+		t.Errorf("log = %s, want the method", buf.Bytes())
+	}
+}
@@ -0,0 +1,243 @@
+// Package grpcx converts between errx errors and gRPC statuses
+package grpcx
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/nordew/go-errx"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// ErrorInfoDomain identifies ErrorInfo details that carry an errx code
+const ErrorInfoDomain = "errx"
+
+// BatchItemDomain identifies ErrorInfo details that carry the error of one
+// failed item of an errx.BatchError; the reason is its code and the metadata
+// holds its message and index or key
+const BatchItemDomain = "errx.batch"
+
+// Metadata keys of batch item details
+const (
+	batchIndexKey   = "index"
+	batchKeyKey     = "key"
+	batchMessageKey = "message"
+)
+
+// CodeToGRPC returns the gRPC code registered for the given errx code
+// Returns codes.Internal if the code has no known mapping
+func CodeToGRPC(code errx.Code) codes.Code {
+	return codes.Code(errx.CodeGRPC(code))
+}
+
+// CodeFromGRPC returns the errx code that best describes a gRPC code
+// Standard gRPC codes map to standard errx codes, and other gRPC codes map to
+// the first registered code using them before falling back to Internal
+// Returns an empty code for codes.OK
+func CodeFromGRPC(c codes.Code) errx.Code {
+	switch c {
+	case codes.OK:
+		return ""
+	case codes.InvalidArgument:
+		return errx.Validation
+	case codes.OutOfRange:
+		return errx.BadRequest
+	case codes.FailedPrecondition:
+		return errx.PreconditionFailed
+	case codes.Unauthenticated:
+		return errx.Unauthorized
+	case codes.PermissionDenied:
+		return errx.Forbidden
+	case codes.NotFound:
+		return errx.NotFound
+	case codes.Aborted:
+		return errx.Conflict
+	case codes.AlreadyExists:
+		return errx.AlreadyExists
+	case codes.DeadlineExceeded:
+		return errx.Timeout
+	case codes.Canceled:
+		return errx.Cancelled
+	case codes.ResourceExhausted:
+		return errx.ResourceExhausted
+	case codes.Unimplemented:
+		return errx.NotImplemented
+	case codes.Unavailable:
+		return errx.Unavailable
+	}
+
+	if code, ok := errx.CodeFromGRPC(uint32(c)); ok {
+		return code
+	}
+	return errx.Internal
+}
+
+// ToStatus converts an error into a gRPC status
+// Errors that already carry a status are returned unchanged; otherwise the
+// errx code is mapped and attached as an ErrorInfo detail along with the fields
+// so FromStatus can restore it exactly, field errors are attached as a
+// BadRequest detail, a retry delay is attached as RetryInfo and each failed
+// item of an errx.BatchError is attached as an ErrorInfo detail in the
+// BatchItemDomain
+// Server errors (HTTP status 5xx) only carry the code and a generic message,
+// as httpx.NewResponse reports them, so internal details such as recovered
+// panic values don't leak to clients; fields are filtered by errx.PublicFields
+// Returns nil for a nil error
+func ToStatus(err error) *status.Status {
+	if err == nil {
+		return nil
+	}
+
+	var e *errx.Error
+	if !errors.As(err, &e) {
+		if st, ok := status.FromError(err); ok {
+			return st
+		}
+	}
+
+	code := errx.GetCode(err)
+	if httpStatus := errx.HTTPStatus(err); httpStatus >= http.StatusInternalServerError {
+		return masked(err, code, httpStatus)
+	}
+	st := status.New(CodeToGRPC(code), errx.Sanitize(errx.GetMessage(err)))
+
+	info := &errdetails.ErrorInfo{
+		Reason: string(code),
+		Domain: ErrorInfoDomain,
+	}
+	if fields := errx.PublicFields(errx.GetFields(err)); len(fields) > 0 {
+		info.Metadata = make(map[string]string, len(fields))
+		for key, value := range fields {
+			info.Metadata[key] = fmt.Sprint(value)
+		}
+	}
+
+	details := []protoadapt.MessageV1{info}
+	if fieldErrors := errx.SanitizeFieldErrors(errx.FieldErrors(err)); len(fieldErrors) > 0 {
+		br := &errdetails.BadRequest{}
+		for _, fe := range fieldErrors {
+			br.FieldViolations = append(br.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       fe.Field,
+				Description: fe.Message,
+				Reason:      fe.Rule,
+			})
+		}
+		details = append(details, br)
+	}
+	if d, ok := errx.RetryAfter(err); ok {
+		details = append(details, &errdetails.RetryInfo{RetryDelay: durationpb.New(d)})
+	}
+	var batch *errx.BatchError
+	if errors.As(err, &batch) {
+		for _, item := range batch.Items() {
+			details = append(details, batchItemInfo(item))
+		}
+	}
+
+	if detailed, detailErr := st.WithDetails(details...); detailErr == nil {
+		return detailed
+	}
+	return st
+}
+
+// masked returns the status of a server error, carrying only its code, a
+// generic message and the retry delay
+func masked(err error, code errx.Code, httpStatus int) *status.Status {
+	st := status.New(CodeToGRPC(code), http.StatusText(httpStatus))
+	details := []protoadapt.MessageV1{&errdetails.ErrorInfo{
+		Reason: string(code),
+		Domain: ErrorInfoDomain,
+	}}
+	if d, ok := errx.RetryAfter(err); ok {
+		details = append(details, &errdetails.RetryInfo{RetryDelay: durationpb.New(d)})
+	}
+	if detailed, detailErr := st.WithDetails(details...); detailErr == nil {
+		return detailed
+	}
+	return st
+}
+
+// FromStatus converts a gRPC status back into an Error
+// The original errx code and fields are restored from the ErrorInfo detail
+// when present; otherwise the gRPC code is mapped
+// Field violations of a BadRequest detail are restored as field errors and
+// batch items as the cause, an errx.BatchError
+// The error is Unreported, as the server reported it
+// Returns nil for a nil or OK status
+func FromStatus(st *status.Status) *errx.Error {
+	if st == nil || st.Code() == codes.OK {
+		return nil
+	}
+
+	b := errx.New(CodeFromGRPC(st.Code())).Unreported().WithMessage(st.Message())
+	batch := errx.NewBatch(0)
+	for _, detail := range st.Details() {
+		switch d := detail.(type) {
+		case *errdetails.ErrorInfo:
+			if d.GetDomain() == BatchItemDomain {
+				addBatchItem(batch, d)
+				continue
+			}
+			if d.GetDomain() != ErrorInfoDomain {
+				continue
+			}
+			b.WithCode(errx.Code(d.GetReason()))
+			for key, value := range d.GetMetadata() {
+				b.WithField(key, value)
+			}
+		case *errdetails.BadRequest:
+			for _, v := range d.GetFieldViolations() {
+				b.WithFieldError(v.GetField(), v.GetReason(), v.GetDescription())
+			}
+		case *errdetails.RetryInfo:
+			if delay := d.GetRetryDelay(); delay != nil {
+				b.WithRetryAfter(delay.AsDuration())
+			}
+		}
+	}
+	if batchErr := batch.Err(); batchErr != nil {
+		b.WithCause(batchErr)
+	}
+	return b.Build()
+}
+
+// batchItemInfo converts a failed batch item into an ErrorInfo detail
+func batchItemInfo(item errx.BatchItem) *errdetails.ErrorInfo {
+	info := &errdetails.ErrorInfo{
+		Reason: string(errx.GetCode(item.Err)),
+		Domain: BatchItemDomain,
+		Metadata: map[string]string{
+			batchMessageKey: errx.Sanitize(errx.GetMessage(item.Err)),
+		},
+	}
+	if item.Index >= 0 {
+		info.Metadata[batchIndexKey] = strconv.Itoa(item.Index)
+	} else {
+		info.Metadata[batchKeyKey] = item.Key
+	}
+	return info
+}
+
+// addBatchItem restores a failed batch item from its ErrorInfo detail
+func addBatchItem(batch *errx.Batch, info *errdetails.ErrorInfo) {
+	meta := info.GetMetadata()
+	err := errx.New(errx.Code(info.GetReason())).Unreported().WithMessage(meta[batchMessageKey]).Build()
+	if index, convErr := strconv.Atoi(meta[batchIndexKey]); convErr == nil {
+		batch.Add(index, err)
+		return
+	}
+	batch.AddKey(meta[batchKeyKey], err)
+}
+
+// StatusJSON encodes the status ToStatus builds for err as protobuf JSON,
+// e.g. for golden file tests with errxtest.AssertGolden
+func StatusJSON(err error) ([]byte, error) {
+	return protojson.Marshal(ToStatus(err).Proto())
+}
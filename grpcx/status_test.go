@@ -0,0 +1,95 @@
+package grpcx
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nordew/go-errx"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+)
+
+func TestToStatusMasksServerErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		code    codes.Code
+		message string
+	}{
+		{"panic", errx.FromPanic("nil map write in billing"), codes.Internal, "Internal Server Error"},
+		{"internal", errx.New(errx.Internal).WithMessage("query failed: password=hunter2").WithField("dsn", "postgres://db").Error(), codes.Internal, "Internal Server Error"},
+		{"unavailable", errx.New(errx.Unavailable).WithMessage("pool exhausted").WithRetryAfter(2 * time.Second).Error(), codes.Unavailable, "Service Unavailable"},
+		{"plain error", errBoom{}, codes.Internal, "Internal Server Error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := ToStatus(tt.err)
+			if st.Code() != tt.code {
+				t.Errorf("code = %s, want %s", st.Code(), tt.code)
+			}
+			if st.Message() != tt.message {
+				t.Errorf("message = %q, want %q", st.Message(), tt.message)
+			}
+			for _, detail := range st.Details() {
+				switch d := detail.(type) {
+				case *errdetails.ErrorInfo:
+					if d.GetReason() != string(errx.GetCode(tt.err)) {
+						t.Errorf("ErrorInfo reason = %q, want %q", d.GetReason(), errx.GetCode(tt.err))
+					}
+					if len(d.GetMetadata()) > 0 {
+						t.Errorf("ErrorInfo metadata = %v, want none", d.GetMetadata())
+					}
+				case *errdetails.RetryInfo:
+				default:
+					t.Errorf("unexpected detail %T", d)
+				}
+			}
+
+			back := FromStatus(st)
+			if back.Code != errx.GetCode(tt.err) {
+				t.Errorf("FromStatus code = %s, want %s", back.Code, errx.GetCode(tt.err))
+			}
+		})
+	}
+}
+
+func TestToStatusClientErrors(t *testing.T) {
+	err := errx.New(errx.Validation).
+		WithMessage("invalid order").
+		WithField("order_id", "o-1").
+		WithFieldError("email", "email", "must be an email").
+		Error()
+
+	st := ToStatus(err)
+	if st.Code() != codes.InvalidArgument || st.Message() != "invalid order" {
+		t.Fatalf("status = %s %q, want InvalidArgument %q", st.Code(), st.Message(), "invalid order")
+	}
+
+	back := FromStatus(st)
+	if back.Code != errx.Validation {
+		t.Errorf("FromStatus code = %s, want %s", back.Code, errx.Validation)
+	}
+	if back.Fields["order_id"] != "o-1" {
+		t.Errorf("FromStatus fields = %v", back.Fields)
+	}
+	if fes := errx.FieldErrors(back); len(fes) != 1 || fes[0].Field != "email" {
+		t.Errorf("FromStatus field errors = %v", fes)
+	}
+}
+
+func TestToStatusDropsPanicField(t *testing.T) {
+	// A panic rewrapped with a client code must still not expose the panic value
+	err := errx.New(errx.BadRequest).WithMessage("bad input").WithField(errx.PanicField, "secret").Error()
+	for _, detail := range ToStatus(err).Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok {
+			if _, found := info.GetMetadata()[errx.PanicField]; found {
+				t.Errorf("ErrorInfo metadata = %v, want no %s", info.GetMetadata(), errx.PanicField)
+			}
+		}
+	}
+}
+
+type errBoom struct{}
+
+func (errBoom) Error() string { return "boom: connection reset by 10.0.0.5" }
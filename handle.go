@@ -0,0 +1,85 @@
+package errx
+
+import (
+	"context"
+	"net/http"
+	"sync"
+)
+
+// ResponseHandler writes the response for an error at an API boundary and
+// performs any side effects, such as logging or clearing a session
+type ResponseHandler func(ctx context.Context, w http.ResponseWriter, err error)
+
+var handlers = struct {
+	mu       sync.RWMutex
+	codes    map[Code]ResponseHandler
+	fallback ResponseHandler
+}{codes: make(map[Code]ResponseHandler)}
+
+// RegisterHandler registers the handler Handle calls for errors with code,
+// replacing any earlier one; a nil handler unregisters it
+// Handlers are meant to be registered once during start-up, so every
+// boundary responds to a code the same way
+func RegisterHandler(code Code, fn ResponseHandler) {
+	handlers.mu.Lock()
+	defer handlers.mu.Unlock()
+	if fn == nil {
+		delete(handlers.codes, code)
+		return
+	}
+	handlers.codes[code] = fn
+}
+
+// RegisterDefaultHandler registers the handler Handle calls for errors whose
+// codes have no handler; nil restores the built-in default, which writes the
+// status mapped from the code with its standard text and the X-Errx-Code and
+// X-Errx-Id headers
+func RegisterDefaultHandler(fn ResponseHandler) {
+	handlers.mu.Lock()
+	defer handlers.mu.Unlock()
+	handlers.fallback = fn
+}
+
+// Handle responds to err with the handler registered for its code, matched
+// as Match does, or the default handler otherwise:
+//
+//	errx.RegisterHandler(errx.Unauthorized, func(ctx context.Context, w http.ResponseWriter, err error) {
+//		http.Redirect(w, requestFrom(ctx), "/login", http.StatusFound)
+//	})
+//
+//	if err := serve(r); err != nil {
+//		errx.Handle(r.Context(), w, err)
+//	}
+//
+// Nothing is written for a nil error
+func Handle(ctx context.Context, w http.ResponseWriter, err error) {
+	if err == nil {
+		return
+	}
+
+	handlers.mu.RLock()
+	var handler ResponseHandler
+	eachCode(err, func(code Code) bool {
+		handler = handlers.codes[code]
+		return handler == nil
+	})
+	if handler == nil {
+		handler = handlers.fallback
+	}
+	handlers.mu.RUnlock()
+
+	if handler == nil {
+		handler = defaultHandler
+	}
+	handler(ctx, w, err)
+}
+
+// defaultHandler writes the status of err with its standard text
+func defaultHandler(_ context.Context, w http.ResponseWriter, err error) {
+	w.Header().Set(CodeHeader, string(GetCode(err)))
+	if id := GetID(err); id != "" {
+		w.Header().Set(IDHeader, id)
+	}
+	status := HTTPStatus(err)
+	http.Error(w, http.StatusText(status), status)
+}
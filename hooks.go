@@ -0,0 +1,71 @@
+package errx
+
+import "sync"
+
+var (
+	hooksMu    sync.RWMutex
+	buildHooks []*func(*Error) *Error
+	wrapHooks  []*func(*Error) *Error
+)
+
+// OnBuild registers a hook run with every Error created by Build or Wrap,
+// before observers see it; the error it returns replaces the created one, and
+// returning nil keeps it
+// This is meant for cross-cutting enrichment such as attaching the hostname,
+// app version or trace ID: Fields is owned by the new error, so hooks can
+// add to it (creating it if nil) and return e
+// Hooks run synchronously on the creating goroutine in registration order;
+// building another error inside a hook runs the hooks again
+// The returned function unregisters the hook
+func OnBuild(fn func(*Error) *Error) (remove func()) {
+	return addHook(&buildHooks, fn)
+}
+
+// OnWrap registers a hook run like those of OnBuild, but only for errors that
+// wrap a cause, e.g. created by Wrap or with WithCause; wrap hooks run after
+// build hooks
+func OnWrap(fn func(*Error) *Error) (remove func()) {
+	return addHook(&wrapHooks, fn)
+}
+
+// addHook appends fn to hooks and returns the function removing it
+func addHook(hooks *[]*func(*Error) *Error, fn func(*Error) *Error) func() {
+	entry := &fn
+
+	hooksMu.Lock()
+	*hooks = append(*hooks, entry)
+	hooksMu.Unlock()
+
+	return func() {
+		hooksMu.Lock()
+		defer hooksMu.Unlock()
+		for i, h := range *hooks {
+			if h == entry {
+				*hooks = append((*hooks)[:i:i], (*hooks)[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// runHooks passes a newly created error through the registered hooks
+func runHooks(e *Error) *Error {
+	hooksMu.RLock()
+	build, wrap := buildHooks, wrapHooks
+	hooksMu.RUnlock()
+
+	for _, fn := range build {
+		if next := (*fn)(e); next != nil {
+			e = next
+		}
+	}
+	if e.Err == nil {
+		return e
+	}
+	for _, fn := range wrap {
+		if next := (*fn)(e); next != nil {
+			e = next
+		}
+	}
+	return e
+}
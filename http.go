@@ -0,0 +1,83 @@
+package errx
+
+import "net/http"
+
+// StatusClientClosedRequest is the non-standard status used for Cancelled
+// errors, meaning the client went away before the response was written
+const StatusClientClosedRequest = 499
+
+// Headers carrying the code and ID of an error in HTTP responses, so clients
+// and proxies can act on them without parsing the body
+const (
+	CodeHeader = "X-Errx-Code"
+	IDHeader   = "X-Errx-Id"
+)
+
+// HTTPStatus returns the HTTP status code matching the error's code
+// Returns 200 for a nil error and 500 for errors without a known code
+func HTTPStatus(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+	return CodeHTTPStatus(GetCode(err))
+}
+
+// CodeHTTPStatus returns the HTTP status code registered for the given code
+// Returns 500 if the code has no known mapping
+func CodeHTTPStatus(code Code) int {
+	if info, ok := LookupCode(code); ok && info.HTTPStatus != 0 {
+		return info.HTTPStatus
+	}
+	return http.StatusInternalServerError
+}
+
+// CodeFromHTTPStatus returns the code that best describes an HTTP status
+// Standard statuses map to standard codes, other statuses map to the first
+// registered code using them, remaining 4xx statuses become BadRequest,
+// 5xx statuses become Internal, and statuses below 400 return an empty code
+func CodeFromHTTPStatus(status int) Code {
+	switch status {
+	case http.StatusBadRequest:
+		return BadRequest
+	case http.StatusUnauthorized:
+		return Unauthorized
+	case http.StatusForbidden:
+		return Forbidden
+	case http.StatusNotFound:
+		return NotFound
+	case http.StatusConflict:
+		return Conflict
+	case http.StatusUnprocessableEntity:
+		return Validation
+	case http.StatusRequestTimeout, http.StatusGatewayTimeout:
+		return Timeout
+	case http.StatusGone:
+		return Gone
+	case http.StatusPreconditionFailed:
+		return PreconditionFailed
+	case http.StatusRequestEntityTooLarge:
+		return PayloadTooLarge
+	case http.StatusUnsupportedMediaType:
+		return UnsupportedMediaType
+	case http.StatusTooManyRequests:
+		return TooManyRequests
+	case StatusClientClosedRequest:
+		return Cancelled
+	case http.StatusNotImplemented:
+		return NotImplemented
+	case http.StatusBadGateway, http.StatusServiceUnavailable:
+		return Unavailable
+	}
+
+	if code, ok := findCode(func(info CodeInfo) bool { return info.HTTPStatus == status }); ok {
+		return code
+	}
+
+	switch {
+	case status >= 500:
+		return Internal
+	case status >= 400:
+		return BadRequest
+	}
+	return ""
+}
@@ -0,0 +1,57 @@
+package httpx
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/nordew/go-errx"
+)
+
+// DebugPath is the conventional path for DebugHandler
+const DebugPath = "/debug/errx"
+
+// DebugHandler serves the errors recorded by errx.RecentErrors as JSON, most
+// recent first; the buffer is enabled with errx.WithRecentErrors
+// The code query parameter keeps only errors with that code and limit caps
+// the number returned:
+//
+//	http.Handle(httpx.DebugPath, httpx.DebugHandler())
+//
+//	GET /debug/errx?code=INTERNAL&limit=20
+//
+// Recorded errors carry internal details, so like net/http/pprof the handler
+// belongs on an internal listener rather than the public API
+func DebugHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		limit := -1
+		if s := query.Get("limit"); s != "" {
+			n, err := strconv.Atoi(s)
+			if err != nil || n < 0 {
+				http.Error(w, "invalid limit", http.StatusBadRequest)
+				return
+			}
+			limit = n
+		}
+		code := errx.Code(query.Get("code"))
+
+		recent := []errx.RecentError{}
+		for _, e := range errx.RecentErrors() {
+			if limit >= 0 && len(recent) == limit {
+				break
+			}
+			if code == "" || e.Code == code {
+				recent = append(recent, e)
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		enc.Encode(struct {
+			Errors []errx.RecentError `json:"errors"`
+		}{recent})
+	})
+}
@@ -0,0 +1,222 @@
+// Package httpx provides net/http helpers for writing errx errors at API boundaries
+package httpx
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"runtime"
+	"strconv"
+
+	"github.com/nordew/go-errx"
+)
+
+// Response is the JSON body written for an error
+type Response struct {
+	Code        errx.Code         `json:"code"`
+	ID          string            `json:"id,omitempty"`
+	NumericCode int               `json:"numeric_code"`
+	Message     string            `json:"message"`
+	Fields      map[string]any    `json:"fields,omitempty"`
+	FieldErrors []errx.FieldError `json:"field_errors,omitempty"`
+	Debug       *Debug            `json:"debug,omitempty"` // Only set in development mode
+}
+
+// Debug holds the internal details of an error included in development mode
+type Debug struct {
+	Causes []string `json:"causes,omitempty"` // Each layer below the outermost, outermost first
+	Stack  []string `json:"stack,omitempty"`  // "function file:line", innermost captured stack or call site
+}
+
+// Writer writes errors with its own output mode, overriding the package-wide
+// mode set with errx.WithMode for the responses it writes
+// The zero Writer follows the package-wide mode, as the package functions do
+type Writer struct {
+	Mode errx.Mode
+}
+
+// NewResponse builds the public response body for an error
+// Causes are never included, server errors (5xx) are reported with a generic
+// message so internal details don't leak to clients, and the installed
+// sanitizer is applied to the message, fields and field errors
+// In development mode the body is built with every detail instead
+func NewResponse(err error) Response {
+	return Writer{}.NewResponse(err)
+}
+
+// WriteError writes err to w as a JSON response with the status mapped from
+// its code and the headers set by SetHeaders
+// Messages of client errors are localized from the request's Accept-Language
+// header if a Localizer is installed with SetLocalizer
+func WriteError(w http.ResponseWriter, r *http.Request, err error) {
+	Writer{}.WriteError(w, r, err)
+}
+
+// NewResponse builds the response body for an error in the writer's mode
+// In production mode it's the public body described by the package
+// NewResponse; in development mode server errors keep their message and
+// fields, nothing is sanitized and Debug lists the causes and stack
+func (wr Writer) NewResponse(err error) Response {
+	status := errx.HTTPStatus(err)
+	resp := Response{
+		Code:        errx.GetCode(err),
+		ID:          errx.GetID(err),
+		NumericCode: errx.NumericCode(err),
+		Message:     http.StatusText(status),
+	}
+
+	if wr.mode() == errx.ModeDevelopment {
+		var e *errx.Error
+		if errors.As(err, &e) {
+			resp.Message = e.Message
+			resp.Fields = e.Fields
+		}
+		resp.FieldErrors = errx.FieldErrors(err)
+		resp.Debug = debug(err)
+		return resp
+	}
+
+	if status >= http.StatusInternalServerError {
+		return resp
+	}
+
+	var e *errx.Error
+	if errors.As(err, &e) {
+		resp.Message = errx.Sanitize(e.Message)
+		resp.Fields = errx.PublicFields(e.Fields)
+	}
+	resp.FieldErrors = errx.SanitizeFieldErrors(errx.FieldErrors(err))
+	return resp
+}
+
+// WriteError writes err like the package WriteError, in the writer's mode
+func (wr Writer) WriteError(w http.ResponseWriter, r *http.Request, err error) {
+	var resp Response
+	if r != nil {
+		resp = wr.Prepare(w.Header(), r.Header.Get("Accept-Language"), err)
+	} else {
+		resp = wr.prepare(w.Header(), err)
+	}
+	w.WriteHeader(errx.HTTPStatus(err))
+	json.NewEncoder(w).Encode(resp)
+}
+
+// Prepare sets the headers WriteError sends for err on h and returns the body
+// it writes, with the message localized from an Accept-Language header
+// It lets frameworks that don't expose an http.ResponseWriter, such as Fiber,
+// write the same response; the status is errx.HTTPStatus(err)
+func Prepare(h http.Header, acceptLanguage string, err error) Response {
+	return Writer{}.Prepare(h, acceptLanguage, err)
+}
+
+// Prepare is like the package Prepare, in the writer's mode
+func (wr Writer) Prepare(h http.Header, acceptLanguage string, err error) Response {
+	resp := wr.prepare(h, err)
+	localize(h, acceptLanguage, err, &resp)
+	return resp
+}
+
+// prepare sets the headers of err on h and returns its unlocalized body
+func (wr Writer) prepare(h http.Header, err error) Response {
+	SetHeaders(h, err)
+	h.Set("Content-Type", "application/json")
+	return wr.NewResponse(err)
+}
+
+// mode returns the writer's mode, falling back to the package-wide mode
+func (wr Writer) mode() errx.Mode {
+	if wr.Mode != 0 {
+		return wr.Mode
+	}
+	return errx.CurrentMode()
+}
+
+// debug collects the causes and stack of an error for development responses
+func debug(err error) *Debug {
+	d := &Debug{}
+	var frames []runtime.Frame
+	for cause := err; cause != nil; cause = errors.Unwrap(cause) {
+		e, ok := cause.(*errx.Error)
+		if cause != err {
+			if ok {
+				d.Causes = append(d.Causes, fmt.Sprintf("[%s] %s", e.Code, e.Message))
+			} else {
+				d.Causes = append(d.Causes, cause.Error())
+			}
+		}
+		if !ok {
+			continue
+		}
+		if stack := e.StackTrace(); len(stack) > 0 {
+			frames = stack
+		} else if src := e.Source(); src.File != "" {
+			frames = []runtime.Frame{{Function: src.Function, File: src.File, Line: src.Line}}
+		}
+	}
+	for _, frame := range frames {
+		d.Stack = append(d.Stack, fmt.Sprintf("%s %s:%d", frame.Function, frame.File, frame.Line))
+	}
+	return d
+}
+
+// SetHeaders sets the response headers describing err: the code and ID as the
+// X-Errx-Code and X-Errx-Id headers, the retry delay reported by
+// errx.RetryAfter as the Retry-After header, a quota set with WithRateLimit as
+// the X-RateLimit-Limit, X-RateLimit-Remaining and X-RateLimit-Reset (Unix
+// seconds) headers, challenges set with WithChallenge as WWW-Authenticate
+// headers, and X-Content-Type-Options
+func SetHeaders(h http.Header, err error) {
+	h.Set(errx.CodeHeader, string(errx.GetCode(err)))
+	if id := errx.GetID(err); id != "" {
+		h.Set(errx.IDHeader, id)
+	}
+	if d, ok := errx.RetryAfter(err); ok {
+		h.Set("Retry-After", strconv.Itoa(int(math.Ceil(d.Seconds()))))
+	}
+	if limit, remaining, reset, ok := errx.RateLimit(err); ok {
+		h.Set("X-RateLimit-Limit", strconv.Itoa(limit))
+		h.Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+		if !reset.IsZero() {
+			h.Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+		}
+	}
+	for _, challenge := range errx.Challenges(err) {
+		h.Add("WWW-Authenticate", challenge.String())
+	}
+	h.Set("X-Content-Type-Options", "nosniff")
+}
+
+// HandlerFunc is an HTTP handler that returns an error instead of writing it
+type HandlerFunc func(w http.ResponseWriter, r *http.Request) error
+
+// ServeHTTP implements http.Handler and writes any returned error with WriteError
+func (f HandlerFunc) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := f(w, r); err != nil {
+		WriteError(w, r, err)
+	}
+}
+
+// Handle adapts a function returning an error into an http.Handler
+func Handle(fn func(w http.ResponseWriter, r *http.Request) error) http.Handler {
+	return HandlerFunc(fn)
+}
+
+// Recover is middleware that recovers panics in next, converting them with
+// errx.FromPanic and writing them with WriteError, so clients get a masked
+// 500 response carrying the error ID
+// http.ErrAbortHandler panics are re-raised so the server can abort the response
+func Recover(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			if v := recover(); v != nil {
+				if v == http.ErrAbortHandler {
+					panic(v)
+				}
+				WriteError(w, r, errx.FromPanic(v))
+			}
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
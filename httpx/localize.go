@@ -0,0 +1,45 @@
+package httpx
+
+import (
+	"net/http"
+	"sync/atomic"
+
+	"github.com/nordew/go-errx"
+)
+
+// Localizer renders the message of an error in the language negotiated from
+// an Accept-Language header
+// It returns the message and the language it's written in, empty if the
+// message wasn't translated; i18n.Catalog implements it
+type Localizer interface {
+	LocalizeAccept(err error, acceptLanguage string) (message, lang string)
+}
+
+var localizer atomic.Pointer[Localizer]
+
+// SetLocalizer installs the Localizer WriteError uses to render messages of
+// client errors (4xx) in the language requested by the client
+// Passing nil removes the localizer
+func SetLocalizer(l Localizer) {
+	if l == nil {
+		localizer.Store(nil)
+		return
+	}
+	localizer.Store(&l)
+}
+
+// localize replaces the message of resp with the localized one, if a
+// localizer is installed, and sets the Content-Language and Vary headers
+func localize(h http.Header, acceptLanguage string, err error, resp *Response) {
+	l := localizer.Load()
+	if l == nil || errx.HTTPStatus(err) >= http.StatusInternalServerError {
+		return
+	}
+
+	msg, lang := (*l).LocalizeAccept(err, acceptLanguage)
+	resp.Message = errx.Sanitize(msg)
+	if lang != "" {
+		h.Set("Content-Language", lang)
+	}
+	h.Add("Vary", "Accept-Language")
+}
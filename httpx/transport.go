@@ -0,0 +1,83 @@
+package httpx
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+
+	"github.com/nordew/go-errx"
+)
+
+// maxErrorBody limits how much of an error response body Transport reads
+const maxErrorBody = 1 << 20
+
+// Transport is an http.RoundTripper that turns error responses written by
+// errx services into errors
+// Responses with a 4xx or 5xx status that carry an X-Errx-Code header, the
+// errx.WireContentType media type or a body that is an httpx Response or an
+// errx wire envelope are consumed and
+// returned as an *errx.Error (wrapped in a *url.Error by http.Client); other
+// responses are passed through untouched
+type Transport struct {
+	Base http.RoundTripper // Underlying transport, http.DefaultTransport if nil
+}
+
+// NewTransport returns a Transport wrapping base
+func NewTransport(base http.RoundTripper) *Transport {
+	return &Transport{Base: base}
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	resp, err := base.RoundTrip(req)
+	if err != nil || resp.StatusCode < http.StatusBadRequest {
+		return resp, err
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(data))
+	if isErrxResponse(resp.Header, data) {
+		return nil, errx.FromHTTPResponse(resp)
+	}
+	return resp, nil
+}
+
+// isErrxResponse reports whether an error response was written by an errx
+// service, as told by its headers or body
+func isErrxResponse(header http.Header, data []byte) bool {
+	if header.Get(errx.CodeHeader) != "" {
+		return true
+	}
+	if mediaType, _, _ := mime.ParseMediaType(header.Get("Content-Type")); mediaType == errx.WireContentType {
+		return true
+	}
+	return isErrxBody(data)
+}
+
+// isErrxBody reports whether data is an errx error body: a code along with
+// the version of a wire envelope or the numeric code every errx body has, so
+// third-party bodies that merely have a "code" member aren't mistaken for one
+func isErrxBody(data []byte) bool {
+	var body struct {
+		Code        any             `json:"code"`
+		Version     json.RawMessage `json:"version"`
+		NumericCode json.RawMessage `json:"numeric_code"`
+	}
+	if json.Unmarshal(data, &body) != nil {
+		return false
+	}
+	code, ok := body.Code.(string)
+	return ok && code != "" && (body.Version != nil || body.NumericCode != nil)
+}
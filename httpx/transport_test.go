@@ -0,0 +1,88 @@
+package httpx
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nordew/go-errx"
+)
+
+func TestIsErrxBody(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want bool
+	}{
+		{"httpx response", `{"code":"NOT_FOUND","numeric_code":4040,"message":"user not found"}`, true},
+		{"wire envelope", `{"version":1,"code":"NOT_FOUND","message":"user not found"}`, true},
+		{"third-party string code", `{"code":"resource_missing","message":"no such customer"}`, false},
+		{"third-party numeric code", `{"code":404,"numeric_code":404}`, false},
+		{"empty code", `{"code":"","numeric_code":4040}`, false},
+		{"no code", `{"numeric_code":4040}`, false},
+		{"not json", `not found`, false},
+		{"empty", ``, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isErrxBody([]byte(tt.body)); got != tt.want {
+				t.Errorf("isErrxBody(%s) = %t, want %t", tt.body, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsErrxResponse(t *testing.T) {
+	thirdParty := []byte(`{"code":"resource_missing"}`)
+	tests := []struct {
+		name   string
+		header http.Header
+		want   bool
+	}{
+		{"code header", http.Header{errx.CodeHeader: {"NOT_FOUND"}}, true},
+		{"wire content type", http.Header{"Content-Type": {errx.WireContentType + "; charset=utf-8"}}, true},
+		{"json content type", http.Header{"Content-Type": {"application/json"}}, false},
+		{"no headers", http.Header{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isErrxResponse(tt.header, thirdParty); got != tt.want {
+				t.Errorf("isErrxResponse = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTransport(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/errx", func(w http.ResponseWriter, r *http.Request) {
+		WriteError(w, r, errx.New(errx.NotFound).WithMessage("user not found").Error())
+	})
+	mux.HandleFunc("/third-party", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"code":"resource_missing"}`))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client := &http.Client{Transport: NewTransport(nil)}
+
+	_, err := client.Get(srv.URL + "/errx")
+	var e *errx.Error
+	if !errors.As(err, &e) || e.Code != errx.NotFound || e.Message != "user not found" {
+		t.Errorf("errx response: err = %v, want [NOT_FOUND] user not found", err)
+	}
+
+	resp, err := client.Get(srv.URL + "/third-party")
+	if err != nil {
+		t.Fatalf("third-party response: err = %v, want the response", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("third-party response: status = %d, want %d", resp.StatusCode, http.StatusNotFound)
+	}
+}
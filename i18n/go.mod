@@ -0,0 +1,9 @@
+module github.com/nordew/go-errx/i18n
+
+go 1.24.1
+
+require github.com/nordew/go-errx v0.0.0
+
+require golang.org/x/text v0.27.0
+
+replace github.com/nordew/go-errx => ../
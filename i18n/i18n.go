@@ -0,0 +1,220 @@
+// Package i18n renders localized messages for errx errors from message catalogs
+package i18n
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/nordew/go-errx"
+	"golang.org/x/text/feature/plural"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/message/catalog"
+)
+
+// Catalog holds localized message templates keyed by language and message key
+// Templates use text/template syntax with the error's message parameters as
+// data, e.g. "user {{.id}} not found"
+// A Catalog is safe for concurrent use
+type Catalog struct {
+	mu       sync.RWMutex
+	fallback language.Tag
+	tags     []language.Tag
+	messages map[language.Tag]map[string]*template.Template
+	plurals  map[language.Tag]map[string]*pluralMessage
+	selector *catalog.Builder // Selects the plural form of plural messages
+	matcher  language.Matcher
+}
+
+// pluralMessage holds the templates of a message for each plural form
+type pluralMessage struct {
+	param string                        // Parameter holding the count
+	forms map[string]*template.Template // Templates keyed by selector
+}
+
+// NewCatalog creates an empty catalog that falls back to the given language
+func NewCatalog(fallback language.Tag) *Catalog {
+	c := &Catalog{
+		fallback: fallback,
+		messages: make(map[language.Tag]map[string]*template.Template),
+		plurals:  make(map[language.Tag]map[string]*pluralMessage),
+		selector: catalog.NewBuilder(),
+	}
+	c.addTag(fallback)
+	return c
+}
+
+// Add registers the message template for a key in a language
+// Returns an error if the template can't be parsed
+func (c *Catalog) Add(tag language.Tag, key, msg string) error {
+	tmpl, err := template.New(key).Parse(msg)
+	if err != nil {
+		return fmt.Errorf("i18n: parsing message %q for %s: %w", key, tag, err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.addTag(tag)
+	c.messages[tag][key] = tmpl
+	return nil
+}
+
+// AddMessages registers several message templates for a language
+func (c *Catalog) AddMessages(tag language.Tag, messages map[string]string) error {
+	for key, msg := range messages {
+		if err := c.Add(tag, key, msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AddPlural registers a message whose template depends on the plural form
+// of a count parameter under the CLDR rules of the language
+// Cases are pairs of a selector and a template as for plural.Selectf:
+// selectors are "zero", "one", "two", "few", "many" and "other", or "=x" and
+// "<x" to match specific counts; the first matching case is used, so "other"
+// should come last
+//
+//	c.AddPlural(language.English, "validation.failed", "Count",
+//		"one", "{{.Count}} item failed validation",
+//		"other", "{{.Count}} items failed validation")
+func (c *Catalog) AddPlural(tag language.Tag, key, countParam string, cases ...string) error {
+	if len(cases) == 0 || len(cases)%2 != 0 {
+		return fmt.Errorf("i18n: plural message %q for %s needs selector and template pairs", key, tag)
+	}
+
+	msg := &pluralMessage{param: countParam, forms: make(map[string]*template.Template)}
+	selectors := make([]any, 0, len(cases))
+	for i := 0; i < len(cases); i += 2 {
+		selector := cases[i]
+		tmpl, err := template.New(key).Parse(cases[i+1])
+		if err != nil {
+			return fmt.Errorf("i18n: parsing message %q for %s: %w", key, tag, err)
+		}
+		msg.forms[selector] = tmpl
+		// The selected message is the selector itself, which picks the template
+		selectors = append(selectors, selector, selector)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.selector.Set(tag, key, plural.Selectf(1, "", selectors...)); err != nil {
+		return fmt.Errorf("i18n: plural message %q for %s: %w", key, tag, err)
+	}
+	c.addTag(tag)
+	c.plurals[tag][key] = msg
+	return nil
+}
+
+// lookup returns the template for a key in a language, selecting the plural
+// form from the params for plural messages; c.mu must be held for reading
+func (c *Catalog) lookup(tag language.Tag, key string, params map[string]any) (*template.Template, bool) {
+	if tmpl, ok := c.messages[tag][key]; ok {
+		return tmpl, true
+	}
+	msg, ok := c.plurals[tag][key]
+	if !ok {
+		return nil, false
+	}
+
+	p := message.NewPrinter(tag, message.Catalog(c.selector))
+	tmpl, ok := msg.forms[p.Sprintf(key, params[msg.param])]
+	return tmpl, ok
+}
+
+// addTag makes the catalog aware of a language; c.mu must be held for writing
+func (c *Catalog) addTag(tag language.Tag) {
+	if _, ok := c.messages[tag]; ok {
+		return
+	}
+	c.messages[tag] = make(map[string]*template.Template)
+	c.plurals[tag] = make(map[string]*pluralMessage)
+	c.tags = append(c.tags, tag)
+	c.matcher = language.NewMatcher(c.tags)
+}
+
+// Languages returns the languages of the catalog, fallback first
+func (c *Catalog) Languages() []language.Tag {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return append([]language.Tag(nil), c.tags...)
+}
+
+// Match returns the catalog language best matching the preferred languages,
+// or the fallback language if none match
+func (c *Catalog) Match(preferred ...language.Tag) language.Tag {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if len(preferred) == 0 {
+		return c.fallback
+	}
+	_, i, confidence := c.matcher.Match(preferred...)
+	if confidence == language.No {
+		return c.fallback
+	}
+	return c.tags[i]
+}
+
+// Render renders the message for a key in the language best matching the
+// preferred languages, falling back to the fallback language, and returns
+// the language the message was rendered in
+// Reports false if neither language has the key
+func (c *Catalog) Render(key string, params map[string]any, preferred ...language.Tag) (string, language.Tag, bool) {
+	tag := c.Match(preferred...)
+
+	c.mu.RLock()
+	tmpl, ok := c.lookup(tag, key, params)
+	if !ok {
+		tag = c.fallback
+		tmpl, ok = c.lookup(tag, key, params)
+	}
+	c.mu.RUnlock()
+	if !ok {
+		return "", language.Und, false
+	}
+
+	var b strings.Builder
+	if err := tmpl.Execute(&b, params); err != nil {
+		return "", language.Und, false
+	}
+	return b.String(), tag, true
+}
+
+// Localize returns the message of err in the language best matching the
+// preferred languages, along with the language it's written in
+// The key set with WithMessageKey is looked up first, then the raw template
+// set with WithTemplate and then the error's code, so catalogs can translate
+// default messages; if none is found the untranslated errx.GetMessage is
+// returned with language.Und
+func (c *Catalog) Localize(err error, preferred ...language.Tag) (string, language.Tag) {
+	if key, params := errx.GetMessageKey(err); key != "" {
+		if msg, tag, ok := c.Render(key, params, preferred...); ok {
+			return msg, tag
+		}
+	}
+	if tmpl, params := errx.GetTemplate(err); tmpl != "" {
+		if msg, tag, ok := c.Render(tmpl, params, preferred...); ok {
+			return msg, tag
+		}
+	}
+	if msg, tag, ok := c.Render(string(errx.GetCode(err)), errx.GetFields(err), preferred...); ok {
+		return msg, tag
+	}
+	return errx.GetMessage(err), language.Und
+}
+
+// LocalizeAccept returns the message of err in the language negotiated from
+// an Accept-Language header, along with the language the message is written
+// in, which is empty if it wasn't translated
+// It implements httpx.Localizer
+func (c *Catalog) LocalizeAccept(err error, acceptLanguage string) (string, string) {
+	preferred, _, _ := language.ParseAcceptLanguage(acceptLanguage)
+	msg, tag := c.Localize(err, preferred...)
+	if tag == language.Und {
+		return msg, ""
+	}
+	return msg, tag.String()
+}
@@ -0,0 +1,60 @@
+package i18n
+
+import (
+	"testing"
+
+	"github.com/nordew/go-errx"
+	"golang.org/x/text/language"
+)
+
+func TestLocalizeAccept(t *testing.T) {
+	c := NewCatalog(language.English)
+	if err := c.AddMessages(language.English, map[string]string{
+		"user.not_found": "user {{.id}} not found",
+		"order.invalid":  "order is invalid",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.AddMessages(language.German, map[string]string{
+		"user.not_found": "Benutzer {{.id}} nicht gefunden",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	userErr := errx.New(errx.NotFound).
+		WithMessage("user not found").
+		WithMessageKey("user.not_found", map[string]any{"id": 42}).
+		Error()
+	orderErr := errx.New(errx.Validation).
+		WithMessage("order rejected").
+		WithMessageKey("order.invalid", nil).
+		Error()
+	untranslated := errx.New(errx.Conflict).WithMessage("version mismatch").Error()
+
+	tests := []struct {
+		name     string
+		err      error
+		accept   string
+		wantMsg  string
+		wantLang string
+	}{
+		{"exact match", userErr, "de-DE,de;q=0.9", "Benutzer 42 nicht gefunden", "de"},
+		{"fallback language", userErr, "fr", "user 42 not found", "en"},
+		{"no header", userErr, "", "user 42 not found", "en"},
+		{"key missing in matched language", orderErr, "de", "order is invalid", "en"},
+		{"untranslated", untranslated, "de", "version mismatch", ""},
+		{"malformed header", userErr, ";;;", "user 42 not found", "en"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg, lang := c.LocalizeAccept(tt.err, tt.accept)
+			if msg != tt.wantMsg {
+				t.Errorf("message = %q, want %q", msg, tt.wantMsg)
+			}
+			if lang != tt.wantLang {
+				t.Errorf("language = %q, want %q", lang, tt.wantLang)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package errx
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+)
+
+// NewID returns a random identifier suitable for WithID, or one from the
+// generator set with WithIDGenerator
+func NewID() string {
+	if fn := idGenerator.Load(); fn != nil {
+		return (*fn)()
+	}
+
+	var b [8]byte
+	rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// WithID sets an identifier for this occurrence of the error, e.g. one
+// generated with NewID, so it can be quoted in support requests and found
+// in logs
+func (b *Builder) WithID(id string) *Builder {
+	b.id = id
+	return b
+}
+
+// ID returns the identifier set with WithID, or an empty string
+func (e *Error) ID() string {
+	return e.id
+}
+
+// GetID returns the outermost identifier in the error chain
+// Returns an empty string if no identifier was set
+func GetID(err error) string {
+	for cause := err; cause != nil; cause = errors.Unwrap(cause) {
+		switch e := cause.(type) {
+		case *Error:
+			if e.id != "" {
+				return e.id
+			}
+		case *RemoteError:
+			if e.ID != "" {
+				return e.ID
+			}
+		}
+	}
+	return ""
+}
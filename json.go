@@ -0,0 +1,138 @@
+package errx
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// jsonError is the JSON representation of one layer of an error chain
+// Layers without a code represent errors that aren't Error values
+type jsonError struct {
+	Code        Code           `json:"code,omitempty"`
+	ID          string         `json:"id,omitempty"`
+	NumericCode int            `json:"numeric_code,omitempty"`
+	Namespace   string         `json:"namespace,omitempty"`
+	Op          string         `json:"op,omitempty"`
+	Message     string         `json:"message"`
+	Fields      map[string]any `json:"fields,omitempty"`
+	Time        time.Time      `json:"time,omitzero"`
+	Source      *Source        `json:"source,omitempty"`
+	FieldErrors []FieldError   `json:"field_errors,omitempty"`
+	Details     map[string]any `json:"details,omitempty"`
+	Cause       *jsonError     `json:"cause,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler
+// Every layer of the cause chain is serialized after applying the installed
+// sanitizer; stack traces are not
+func (e *Error) MarshalJSON() ([]byte, error) {
+	return json.Marshal(toJSONError(e))
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+// Wrapped Error values are restored with their codes intact, while other
+// causes are restored as opaque errors carrying the original text
+func (e *Error) UnmarshalJSON(data []byte) error {
+	var je jsonError
+	if err := json.Unmarshal(data, &je); err != nil {
+		return err
+	}
+
+	*e = Error{
+		Code:    je.Code,
+		Message: je.Message,
+		Fields:  je.Fields,
+		Err:     je.Cause.toError(),
+
+		op:          je.Op,
+		namespace:   je.Namespace,
+		template:    je.Message,
+		time:        je.Time,
+		source:      je.Source,
+		fieldErrors: je.FieldErrors,
+		id:          je.ID,
+		details:     je.Details,
+	}
+	return nil
+}
+
+// toJSONError converts an error chain into its JSON representation
+func toJSONError(err error) *jsonError {
+	if err == nil {
+		return nil
+	}
+
+	if e, ok := err.(*Error); ok {
+		return &jsonError{
+			Code:        e.Code,
+			ID:          e.id,
+			NumericCode: CodeNumeric(e.Code),
+			Namespace:   e.namespace,
+			Op:          e.op,
+			Message:     Sanitize(e.Message),
+			Fields:      SanitizeFields(e.Fields),
+			Time:        e.time,
+			Source:      e.jsonSource(),
+			FieldErrors: SanitizeFieldErrors(e.fieldErrors),
+			Details:     SanitizeFields(e.details),
+			Cause:       toJSONError(e.Err),
+		}
+	}
+	return &jsonError{
+		Message: Sanitize(err.Error()),
+		Cause:   toJSONError(errors.Unwrap(err)),
+	}
+}
+
+// toError converts a JSON representation back into an error chain
+func (je *jsonError) toError() error {
+	if je == nil {
+		return nil
+	}
+
+	if je.Code != "" {
+		return &Error{
+			Code:    je.Code,
+			Message: je.Message,
+			Fields:  je.Fields,
+			Err:     je.Cause.toError(),
+
+			op:          je.Op,
+			namespace:   je.Namespace,
+			template:    je.Message,
+			time:        je.Time,
+			source:      je.Source,
+			fieldErrors: je.FieldErrors,
+			id:          je.ID,
+			details:     je.Details,
+		}
+	}
+	return &opaqueError{
+		msg: je.Message,
+		err: je.Cause.toError(),
+	}
+}
+
+// opaqueError restores a decoded error that wasn't an Error value
+type opaqueError struct {
+	msg string
+	err error
+}
+
+func (e *opaqueError) Error() string {
+	return e.msg
+}
+
+func (e *opaqueError) Unwrap() error {
+	return e.err
+}
+
+// jsonSource returns the call site to serialize, or nil if none was recorded
+func (e *Error) jsonSource() *Source {
+	src := e.Source()
+	if src.File == "" {
+		return nil
+	}
+	return &src
+}
@@ -0,0 +1,92 @@
+package errx
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// JSONAPIError is a JSON:API error object
+type JSONAPIError struct {
+	ID     string         `json:"id,omitempty"`
+	Status string         `json:"status"`
+	Code   Code           `json:"code"`
+	Title  string         `json:"title"`
+	Detail string         `json:"detail,omitempty"`
+	Source *JSONAPISource `json:"source,omitempty"`
+	Meta   map[string]any `json:"meta,omitempty"`
+}
+
+// JSONAPISource points at the part of the request document that caused an error
+type JSONAPISource struct {
+	Pointer   string `json:"pointer,omitempty"`
+	Parameter string `json:"parameter,omitempty"`
+}
+
+// ToJSONAPIErrors converts an error into the objects of a JSON:API errors member
+// Each field error becomes its own object whose source points at the
+// attribute, members of a MultiError are converted separately, and any other
+// error becomes a single object; fields are exposed as meta
+// As with ToProblem, only messages of Error values are used as the detail,
+// the installed sanitizer is applied and server errors (5xx) only carry the
+// status text
+func ToJSONAPIErrors(err error) []JSONAPIError {
+	if err == nil {
+		return nil
+	}
+
+	var multi *MultiError
+	if errors.As(err, &multi) {
+		var objs []JSONAPIError
+		for _, member := range multi.Errors() {
+			objs = append(objs, ToJSONAPIErrors(member)...)
+		}
+		return objs
+	}
+
+	status := HTTPStatus(err)
+	obj := JSONAPIError{
+		ID:     GetID(err),
+		Status: strconv.Itoa(status),
+		Code:   GetCode(err),
+		Title:  http.StatusText(status),
+	}
+	if status >= http.StatusInternalServerError {
+		obj.Detail = obj.Title
+		return []JSONAPIError{obj}
+	}
+
+	var e *Error
+	if errors.As(err, &e) {
+		obj.Detail = Sanitize(e.Message)
+		obj.Meta = PublicFields(e.Fields)
+	}
+
+	fieldErrors := SanitizeFieldErrors(FieldErrors(err))
+	if len(fieldErrors) == 0 {
+		return []JSONAPIError{obj}
+	}
+
+	objs := make([]JSONAPIError, 0, len(fieldErrors))
+	for _, fe := range fieldErrors {
+		fieldObj := obj
+		fieldObj.Detail = fe.Message
+		fieldObj.Source = &JSONAPISource{Pointer: attributePointer(fe.Field)}
+		objs = append(objs, fieldObj)
+	}
+	return objs
+}
+
+// attributePointer returns the JSON pointer to an attribute given as a dotted path
+func attributePointer(field string) string {
+	escaper := strings.NewReplacer("~", "~0", "/", "~1")
+
+	var b strings.Builder
+	b.WriteString("/data/attributes")
+	for _, part := range strings.Split(field, ".") {
+		b.WriteByte('/')
+		b.WriteString(escaper.Replace(part))
+	}
+	return b.String()
+}
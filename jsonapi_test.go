@@ -0,0 +1,38 @@
+package errx
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestToJSONAPIErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		details []string
+		meta    bool
+	}{
+		{"client error", New(NotFound).WithMessage("user not found").WithField("user_id", 7).Error(), []string{"user not found"}, true},
+		{"field errors", New(Validation).WithMessage("invalid").WithFieldError("email", "email", "must be an email").Error(), []string{"must be an email"}, false},
+		{"internal", New(Internal).WithMessage("query failed: password=hunter2").WithField("dsn", "postgres://db").Error(), []string{http.StatusText(http.StatusInternalServerError)}, false},
+		{"panic", FromPanic("nil map write"), []string{http.StatusText(http.StatusInternalServerError)}, false},
+		{"joined", Join(New(NotFound).WithMessage("a").Error(), New(Internal).WithMessage("secret").Error()), []string{"a", http.StatusText(http.StatusInternalServerError)}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			objs := ToJSONAPIErrors(tt.err)
+			if len(objs) != len(tt.details) {
+				t.Fatalf("got %d objects, want %d: %+v", len(objs), len(tt.details), objs)
+			}
+			for i, obj := range objs {
+				if obj.Detail != tt.details[i] {
+					t.Errorf("object %d detail = %q, want %q", i, obj.Detail, tt.details[i])
+				}
+			}
+			if got := len(objs[0].Meta) > 0; got != tt.meta {
+				t.Errorf("meta = %v, want present %t", objs[0].Meta, tt.meta)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+// Package logrusx adapts errx errors to logrus fields
+package logrusx
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/nordew/go-errx"
+	"github.com/sirupsen/logrus"
+)
+
+// Keys used for the error attributes added to log entries
+const (
+	CodeKey    = "error_code"
+	MessageKey = "error_message"
+	CauseKey   = "error_cause"
+	StackKey   = "error_stack"
+)
+
+// Fields converts an error into logrus fields
+// The code, message, cause and stack use the keys above, and the error's own
+// fields are added under their own names; the installed sanitizer is applied
+// to the messages and field values
+func Fields(err error) logrus.Fields {
+	var e *errx.Error
+	if !errors.As(err, &e) {
+		if err == nil {
+			return logrus.Fields{}
+		}
+		return logrus.Fields{MessageKey: errx.Sanitize(err.Error())}
+	}
+
+	fields := make(logrus.Fields, len(e.Fields)+4)
+	for key, value := range errx.SanitizeFields(e.Fields) {
+		fields[key] = value
+	}
+	fields[CodeKey] = string(e.Code)
+	fields[MessageKey] = errx.Sanitize(e.Message)
+	if e.Err != nil {
+		fields[CauseKey] = errx.Sanitize(e.Err.Error())
+	}
+	if frames := e.StackTrace(); len(frames) > 0 {
+		stack := make([]string, 0, len(frames))
+		for _, frame := range frames {
+			stack = append(stack, fmt.Sprintf("%s %s:%d", frame.Function, frame.File, frame.Line))
+		}
+		fields[StackKey] = stack
+	}
+	return fields
+}
+
+// WithError returns an entry with the error and its fields attached
+func WithError(logger logrus.FieldLogger, err error) *logrus.Entry {
+	return logger.WithError(err).WithFields(Fields(err))
+}
+
+// Hook extracts errx fields whenever an error is attached to an entry with
+// WithError; fields already present on the entry are never overwritten
+type Hook struct {
+	// LogLevels limits the levels the hook fires for; all levels if empty
+	LogLevels []logrus.Level
+}
+
+// NewHook creates a Hook that fires for the given levels, or all levels if none
+func NewHook(levels ...logrus.Level) *Hook {
+	return &Hook{LogLevels: levels}
+}
+
+// Levels implements logrus.Hook
+func (h *Hook) Levels() []logrus.Level {
+	if len(h.LogLevels) == 0 {
+		return logrus.AllLevels
+	}
+	return h.LogLevels
+}
+
+// Fire implements logrus.Hook
+func (h *Hook) Fire(entry *logrus.Entry) error {
+	err, ok := entry.Data[logrus.ErrorKey].(error)
+	if !ok {
+		return nil
+	}
+
+	for key, value := range Fields(err) {
+		if _, exists := entry.Data[key]; !exists {
+			entry.Data[key] = value
+		}
+	}
+	return nil
+}
+
+// Level returns the logrus level matching an error's severity
+func Level(err error) logrus.Level {
+	switch errx.GetSeverity(err) {
+	case errx.SeverityInfo:
+		return logrus.InfoLevel
+	case errx.SeverityWarning:
+		return logrus.WarnLevel
+	}
+	return logrus.ErrorLevel
+}
+
+// Log logs an error at the level derived from its severity with its fields attached
+func Log(logger *logrus.Logger, err error) {
+	if err == nil {
+		return
+	}
+	WithError(logger, err).Log(Level(err), errx.Sanitize(errx.GetMessage(err)))
+}
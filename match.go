@@ -0,0 +1,39 @@
+package errx
+
+// Handlers maps codes to the functions handling errors with them, for Match
+// Being a map, handler sets can be declared once, copied and combined with
+// maps.Copy
+type Handlers map[Code]func(err error)
+
+// Match calls the handler registered for the code of err, replacing a switch
+// over IsCode cases:
+//
+//	errx.Match(err, errx.Handlers{
+//		errx.NotFound:   func(err error) { status = http.StatusNotFound },
+//		errx.Validation: func(err error) { status = http.StatusUnprocessableEntity },
+//	}, func(err error) { status = http.StatusInternalServerError })
+//
+// Codes are matched as IsCode matches them, so a wrapping Error's code
+// decides and each branch of a joined error is tried in order; fallback, if
+// not nil, handles errors no handler matches
+// Nothing is called for a nil error; Match reports whether a handler or
+// fallback was called
+func Match(err error, handlers Handlers, fallback func(err error)) bool {
+	if err == nil {
+		return false
+	}
+	var handler func(error)
+	eachCode(err, func(code Code) bool {
+		handler = handlers[code]
+		return handler == nil
+	})
+	if handler != nil {
+		handler(err)
+		return true
+	}
+	if fallback != nil {
+		fallback(err)
+		return true
+	}
+	return false
+}
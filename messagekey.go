@@ -0,0 +1,30 @@
+package errx
+
+import "errors"
+
+// WithMessageKey sets the catalog key and named parameters used to render a
+// localized message at the boundary, e.g. with the i18n module
+// The message set with WithMessage, or the code's default message, remains
+// the untranslated fallback
+func (b *Builder) WithMessageKey(key string, params map[string]any) *Builder {
+	b.messageKey = key
+	b.messageParams = params
+	return b
+}
+
+// MessageKey returns the catalog key and parameters set with WithMessageKey
+func (e *Error) MessageKey() (string, map[string]any) {
+	return e.messageKey, e.messageParams
+}
+
+// GetMessageKey returns the outermost catalog key in the error chain and its
+// parameters
+// Returns an empty key if none was set
+func GetMessageKey(err error) (string, map[string]any) {
+	for cause := err; cause != nil; cause = errors.Unwrap(cause) {
+		if e, ok := cause.(*Error); ok && e.messageKey != "" {
+			return e.messageKey, e.messageParams
+		}
+	}
+	return "", nil
+}
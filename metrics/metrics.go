@@ -0,0 +1,114 @@
+// Package metrics exposes Prometheus counters of errx errors by code
+package metrics
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/nordew/go-errx"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Option configures a Collector
+type Option func(*Collector)
+
+// WithNamespace sets the metric namespace, "errx" by default
+func WithNamespace(namespace string) Option {
+	return func(c *Collector) {
+		c.namespace = namespace
+	}
+}
+
+// WithLabel adds a label whose value is extracted from each counted error,
+// e.g. the operation or domain that produced it
+func WithLabel(name string, value func(*errx.Error) string) Option {
+	return func(c *Collector) {
+		c.labels = append(c.labels, label{name: name, value: value})
+	}
+}
+
+type label struct {
+	name  string
+	value func(*errx.Error) string
+}
+
+// Collector counts errors by code and implements prometheus.Collector
+type Collector struct {
+	namespace string
+	labels    []label
+	counter   *prometheus.CounterVec
+
+	mu     sync.Mutex
+	remove func()
+}
+
+// NewCollector creates a Collector exposing the <namespace>_errors_total counter
+func NewCollector(opts ...Option) *Collector {
+	c := &Collector{namespace: "errx"}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	names := []string{"code"}
+	for _, l := range c.labels {
+		names = append(names, l.name)
+	}
+	c.counter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: c.namespace,
+		Name:      "errors_total",
+		Help:      "Total number of errx errors by code.",
+	}, names)
+	return c
+}
+
+// Describe implements prometheus.Collector
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	c.counter.Describe(ch)
+}
+
+// Collect implements prometheus.Collector
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.counter.Collect(ch)
+}
+
+// Count increments the counter for an error
+// Errors that aren't errx errors are counted under their GetCode result
+func (c *Collector) Count(err error) {
+	if err == nil {
+		return
+	}
+
+	var e *errx.Error
+	if !errors.As(err, &e) {
+		e = &errx.Error{Code: errx.GetCode(err)}
+	}
+	c.count(e)
+}
+
+func (c *Collector) count(e *errx.Error) {
+	values := make([]string, 0, len(c.labels)+1)
+	values = append(values, string(e.Code))
+	for _, l := range c.labels {
+		values = append(values, l.value(e))
+	}
+	c.counter.WithLabelValues(values...).Inc()
+}
+
+// Enable counts every error created by errx Build and Wrap calls automatically
+func (c *Collector) Enable() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.remove == nil {
+		c.remove = errx.Observe(c.count)
+	}
+}
+
+// Disable stops counting errors automatically
+func (c *Collector) Disable() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.remove != nil {
+		c.remove()
+		c.remove = nil
+	}
+}
@@ -0,0 +1,65 @@
+package errx
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"sync/atomic"
+)
+
+// Mode selects how much detail errors reveal when printed or written to
+// clients
+type Mode int
+
+// Output modes
+const (
+	ModeProduction  Mode = iota + 1 // Terse output with masked server errors, the default
+	ModeDevelopment                 // Verbose output with causes, stacks and fields
+)
+
+// String returns the lowercase name of the mode
+func (m Mode) String() string {
+	switch m {
+	case ModeProduction:
+		return "production"
+	case ModeDevelopment:
+		return "development"
+	}
+	return "unknown"
+}
+
+var mode atomic.Int32
+
+// WithMode sets the package-wide output mode; 0 restores ModeProduction
+// In development mode the verbose form printed by %+v lists the fields of
+// every layer next to its stack frames or call site, and HTTP writers such as
+// httpx.WriteError include causes and stacks in response bodies without
+// masking server errors; Error stays terse, as it ends up in messages
+// formatted by Errorf and fmt.Errorf
+func WithMode(m Mode) ConfigOption {
+	return func() {
+		mode.Store(int32(m))
+	}
+}
+
+// CurrentMode returns the package-wide output mode
+func CurrentMode() Mode {
+	if m := mode.Load(); m != 0 {
+		return Mode(m)
+	}
+	return ModeProduction
+}
+
+// writeFields writes the fields of an error in key order as " {k=v, ...}"
+func writeFields(w io.Writer, fields map[string]any) {
+	if len(fields) == 0 {
+		return
+	}
+	pairs := make([]string, 0, len(fields))
+	for _, key := range slices.Sorted(maps.Keys(fields)) {
+		pairs = append(pairs, fmt.Sprintf("%s=%v", key, fields[key]))
+	}
+	fmt.Fprintf(w, " {%s}", strings.Join(pairs, ", "))
+}
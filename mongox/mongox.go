@@ -0,0 +1,95 @@
+// Package mongox maps errors from the official MongoDB driver to errx errors
+package mongox
+
+import (
+	"context"
+	"errors"
+	"regexp"
+
+	"github.com/nordew/go-errx"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/topology"
+)
+
+// Field keys used for the details of a MongoDB error
+const (
+	CodeField       = "mongo_code"
+	CollectionField = "collection"
+	IndexField      = "index"
+)
+
+// MongoDB server error codes with a specific mapping
+const (
+	codeUnauthorized         = 13
+	codeAuthenticationFailed = 18
+	codeWriteConflict        = 112
+)
+
+// duplicateKeyPattern extracts the namespace and index from a duplicate key message
+var duplicateKeyPattern = regexp.MustCompile(`collection: (\S+) index: (\S+)`)
+
+// MapError wraps a MongoDB driver error in an Error with a code describing it
+// mongo.ErrNoDocuments maps to NotFound, duplicate key errors to AlreadyExists
+// with the collection and index as fields, server selection failures to
+// Unavailable, cancellations to Cancelled and timeouts to Timeout; server
+// error codes are kept as a field
+// Errors that already carry an errx code are returned unchanged
+// Returns nil for a nil error
+func MapError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var e *errx.Error
+	if errors.As(err, &e) {
+		return err
+	}
+
+	b := errx.New(Code(err)).WithCause(err).WithCallerSkip(1)
+
+	var se mongo.ServerError
+	if errors.As(err, &se) {
+		if codes := se.ErrorCodes(); len(codes) > 0 {
+			b.WithField(CodeField, codes[0])
+		}
+		if se.HasErrorCode(codeWriteConflict) {
+			b.WithRetryable(true)
+		}
+	}
+	if mongo.IsDuplicateKeyError(err) {
+		if m := duplicateKeyPattern.FindStringSubmatch(err.Error()); m != nil {
+			b.WithField(CollectionField, m[1]).WithField(IndexField, m[2])
+		}
+	}
+	return b.Build()
+}
+
+// Code returns the errx code describing a MongoDB driver error
+func Code(err error) errx.Code {
+	var sse topology.ServerSelectionError
+	switch {
+	case errors.Is(err, mongo.ErrNoDocuments):
+		return errx.NotFound
+	case errors.Is(err, context.Canceled):
+		return errx.Cancelled
+	case mongo.IsDuplicateKeyError(err):
+		return errx.AlreadyExists
+	case errors.As(err, &sse), errors.Is(err, mongo.ErrClientDisconnected), mongo.IsNetworkError(err):
+		return errx.Unavailable
+	case mongo.IsTimeout(err):
+		return errx.Timeout
+	}
+
+	var se mongo.ServerError
+	if errors.As(err, &se) {
+		switch {
+		case se.HasErrorCode(codeAuthenticationFailed):
+			return errx.Unauthorized
+		case se.HasErrorCode(codeUnauthorized):
+			return errx.Forbidden
+		case se.HasErrorCode(codeWriteConflict):
+			return errx.Conflict
+		}
+	}
+	return errx.Internal
+}
@@ -0,0 +1,150 @@
+package errx
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync/atomic"
+)
+
+// JoinStrategy chooses the primary code of a joined error from its members
+type JoinStrategy func(errs []error) Code
+
+// MostSevere picks the code of the member with the highest severity,
+// preferring earlier members on ties
+func MostSevere(errs []error) Code {
+	var primary error
+	for _, err := range errs {
+		if primary == nil || GetSeverity(err) > GetSeverity(primary) {
+			primary = err
+		}
+	}
+	return GetCode(primary)
+}
+
+// FirstCode picks the code of the first member
+func FirstCode(errs []error) Code {
+	if len(errs) == 0 {
+		return ""
+	}
+	return GetCode(errs[0])
+}
+
+var joinStrategy atomic.Pointer[JoinStrategy]
+
+// SetJoinStrategy sets the strategy used by Join, MostSevere by default
+func SetJoinStrategy(s JoinStrategy) {
+	if s == nil {
+		joinStrategy.Store(nil)
+		return
+	}
+	joinStrategy.Store(&s)
+}
+
+// Join combines errors into a MultiError whose primary code is chosen by the
+// strategy set with SetJoinStrategy
+// Nil errors are discarded; returns nil if every error is nil
+func Join(errs ...error) error {
+	strategy := JoinStrategy(MostSevere)
+	if s := joinStrategy.Load(); s != nil {
+		strategy = *s
+	}
+	return JoinWith(strategy, errs...)
+}
+
+// JoinWith is like Join but uses the given strategy to choose the primary code
+func JoinWith(strategy JoinStrategy, errs ...error) error {
+	members := make([]error, 0, len(errs))
+	for _, err := range errs {
+		if err != nil {
+			members = append(members, err)
+		}
+	}
+	if len(members) == 0 {
+		return nil
+	}
+
+	return &MultiError{
+		errs: members,
+		code: strategy(members),
+	}
+}
+
+// MultiError aggregates several errors under a primary code
+// errors.Is, errors.As and IsCode match each member, while GetCode and
+// errors.As with an *Error target see the primary member
+type MultiError struct {
+	errs []error
+	code Code
+}
+
+// Error implements the error interface, joining the member messages with "; "
+func (m *MultiError) Error() string {
+	msgs := make([]string, len(m.errs))
+	for i, err := range m.errs {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap returns the members for errors.Is and errors.As
+func (m *MultiError) Unwrap() []error {
+	return m.errs
+}
+
+// Errors returns the members
+func (m *MultiError) Errors() []error {
+	return m.errs
+}
+
+// Code returns the primary code
+func (m *MultiError) Code() Code {
+	return m.code
+}
+
+// Codes returns the code of every member
+func (m *MultiError) Codes() []Code {
+	codes := make([]Code, len(m.errs))
+	for i, err := range m.errs {
+		codes[i] = GetCode(err)
+	}
+	return codes
+}
+
+// As allows errors.As to treat the MultiError as its primary member
+func (m *MultiError) As(target any) bool {
+	t, ok := target.(**Error)
+	if !ok {
+		return false
+	}
+
+	for _, err := range m.errs {
+		var e *Error
+		if GetCode(err) == m.code && errors.As(err, &e) {
+			*t = e
+			return true
+		}
+	}
+	*t = &Error{Code: m.code, Message: DefaultMessage(m.code), Err: m.errs[0]}
+	return true
+}
+
+// MarshalJSON implements json.Marshaler, serializing the primary code and every member
+func (m *MultiError) MarshalJSON() ([]byte, error) {
+	members := make([]*jsonError, len(m.errs))
+	for i, err := range m.errs {
+		members[i] = toJSONError(err)
+	}
+
+	return json.Marshal(struct {
+		Code        Code         `json:"code"`
+		NumericCode int          `json:"numeric_code"`
+		Message     string       `json:"message"`
+		Errors      []*jsonError `json:"errors"`
+	}{
+		Code:        m.code,
+		NumericCode: CodeNumeric(m.code),
+		Message:     Sanitize(GetMessage(m)),
+		Errors:      members,
+	})
+}
@@ -0,0 +1,71 @@
+package errx
+
+// Must returns v, or panics with err as an Error if it isn't nil, for
+// initialization code where an error is a programming or deployment mistake
+// An error that isn't an Error value becomes the cause of an Internal error
+// (or the code set with WithFallbackCode)
+//
+//	var tmpl = errx.Must(template.ParseFS(files, "*.html"))
+func Must[T any](v T, err error) T {
+	if err != nil {
+		panic(coded(err, 1))
+	}
+	return v
+}
+
+// tryPanic is the value Try panics with, so boundaries can tell a returned
+// error from a genuine panic
+type tryPanic struct {
+	err *Error
+}
+
+// Try panics with err if it isn't nil, returning it from the nearest
+// deferred Catch or boundary using FromPanic, such as httpx.Recover, as if
+// the function had returned it
+// An error that isn't an Error value is coded as Must does
+//
+//	func load(path string) (cfg Config, err error) {
+//		defer errx.Catch(&err)
+//		data := errx.Try1(os.ReadFile(path))
+//		errx.Try(json.Unmarshal(data, &cfg))
+//		return cfg, nil
+//	}
+func Try(err error) {
+	if err != nil {
+		panic(tryPanic{err: coded(err, 1)})
+	}
+}
+
+// Try1 returns v, or panics with err as Try does if it isn't nil
+func Try1[T any](v T, err error) T {
+	if err != nil {
+		panic(tryPanic{err: coded(err, 1)})
+	}
+	return v
+}
+
+// Catch recovers a panic raised by Try or Try1 and stores its error in
+// *errp; other panics are propagated
+// It must be deferred directly:
+//
+//	defer errx.Catch(&err)
+func Catch(errp *error) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	t, ok := r.(tryPanic)
+	if !ok {
+		panic(r)
+	}
+	*errp = t.err
+}
+
+// coded returns err if it's an Error, or an error with the fallback code
+// wrapping it, recorded at the caller skip frames above the caller of coded
+func coded(err error, skip int) *Error {
+	if e, ok := err.(*Error); ok {
+		return e
+	}
+	return New(currentFallbackCode()).WithMessage("unexpected error").WithCause(err).build(skip + 1)
+}
@@ -0,0 +1,12 @@
+module github.com/nordew/go-errx/mysqlx
+
+go 1.24.1
+
+require (
+	github.com/go-sql-driver/mysql v1.9.3
+	github.com/nordew/go-errx v0.0.0
+)
+
+require filippo.io/edwards25519 v1.1.0 // indirect
+
+replace github.com/nordew/go-errx => ../
@@ -0,0 +1,94 @@
+// Package mysqlx maps MySQL driver errors to errx errors
+package mysqlx
+
+import (
+	"errors"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/nordew/go-errx"
+	"github.com/nordew/go-errx/sqlx"
+)
+
+// NumberField is the field key holding the MySQL error number
+const NumberField = "mysql_number"
+
+// MapError wraps a MySQL error in an Error with a code derived from its error
+// number, keeping the number as a field
+// Duplicate entries map to AlreadyExists, deadlocks to a retryable Conflict,
+// lock wait timeouts to Timeout and foreign key failures to Conflict; other
+// errors are mapped by their SQLSTATE or by sqlx.Code
+// Errors that already carry an errx code are returned unchanged
+// Returns nil for a nil error
+func MapError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var e *errx.Error
+	if errors.As(err, &e) {
+		return err
+	}
+
+	var myErr *mysql.MySQLError
+	if !errors.As(err, &myErr) {
+		if errors.Is(err, mysql.ErrInvalidConn) {
+			return errx.WrapSkip(1, err, errx.Unavailable, "")
+		}
+		return errx.WrapSkip(1, err, sqlx.Code(err), "")
+	}
+
+	b := errx.New(Code(myErr)).
+		WithCause(err).
+		WithField(NumberField, myErr.Number).
+		WithCallerSkip(1)
+	if myErr.Number == erDeadlock {
+		b.WithRetryable(true)
+	}
+	return b.Build()
+}
+
+// MySQL server error numbers with a specific mapping
+const (
+	erDupEntry           = 1062
+	erDeadlock           = 1213
+	erLockWaitTimeout    = 1205
+	erRowIsReferenced    = 1451
+	erNoReferencedRow    = 1452
+	erRowIsReferenced2   = 1216
+	erNoReferencedRow2   = 1217
+	erBadNull            = 1048
+	erDataTooLong        = 1406
+	erAccessDenied       = 1045
+	erTableAccessDenied  = 1142
+	erTooManyConnections = 1040
+	erQueryTimeout       = 3024
+	erQueryInterrupted   = 1317
+)
+
+// Code returns the errx code describing a MySQL error
+// Numbers without a specific mapping fall back to the SQLSTATE, then Internal
+func Code(err *mysql.MySQLError) errx.Code {
+	switch err.Number {
+	case erDupEntry:
+		return errx.AlreadyExists
+	case erDeadlock, erRowIsReferenced, erNoReferencedRow, erRowIsReferenced2, erNoReferencedRow2:
+		return errx.Conflict
+	case erLockWaitTimeout, erQueryTimeout:
+		return errx.Timeout
+	case erQueryInterrupted:
+		return errx.Cancelled
+	case erBadNull, erDataTooLong:
+		return errx.BadRequest
+	case erAccessDenied:
+		return errx.Unauthorized
+	case erTableAccessDenied:
+		return errx.Forbidden
+	case erTooManyConnections:
+		return errx.Unavailable
+	}
+
+	if code, ok := sqlx.CodeFromSQLState(string(err.SQLState[:])); ok {
+		return code
+	}
+	return errx.Internal
+}
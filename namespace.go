@@ -0,0 +1,140 @@
+package errx
+
+import "errors"
+
+// Factory creates errors tagged with a namespace
+type Factory struct {
+	namespace string
+}
+
+// Namespace returns a Factory whose errors are tagged with the given namespace
+func Namespace(name string) *Factory {
+	return &Factory{namespace: name}
+}
+
+// Name returns the namespace of the factory
+func (f *Factory) Name() string {
+	return f.namespace
+}
+
+// GetNamespace returns the namespace of the outermost error in the chain that has one
+// Returns an empty string if no namespace was set
+func GetNamespace(err error) string {
+	for cause := err; cause != nil; cause = errors.Unwrap(cause) {
+		if e, ok := cause.(*Error); ok && e.namespace != "" {
+			return e.namespace
+		}
+	}
+	return ""
+}
+
+// New creates a new Builder with the specified code
+func (f *Factory) New(code Code) *Builder {
+	return &Builder{code: code, namespace: f.namespace}
+}
+
+// NewBadRequest creates an error builder for BadRequest errors
+func (f *Factory) NewBadRequest() *Builder {
+	return f.New(BadRequest)
+}
+
+// NewNotFound creates an error builder for NotFound errors
+func (f *Factory) NewNotFound() *Builder {
+	return f.New(NotFound)
+}
+
+// NewConflict creates an error builder for Conflict errors
+func (f *Factory) NewConflict() *Builder {
+	return f.New(Conflict)
+}
+
+// NewInternal creates an error builder for Internal errors
+func (f *Factory) NewInternal() *Builder {
+	return f.New(Internal)
+}
+
+// NewAlreadyExists creates an error builder for AlreadyExists errors
+func (f *Factory) NewAlreadyExists() *Builder {
+	return f.New(AlreadyExists)
+}
+
+// NewUnauthorized creates an error builder for Unauthorized errors
+func (f *Factory) NewUnauthorized() *Builder {
+	return f.New(Unauthorized)
+}
+
+// NewForbidden creates an error builder for Forbidden errors
+func (f *Factory) NewForbidden() *Builder {
+	return f.New(Forbidden)
+}
+
+// NewTimeout creates an error builder for Timeout errors
+func (f *Factory) NewTimeout() *Builder {
+	return f.New(Timeout)
+}
+
+// NewValidation creates an error builder for Validation errors
+func (f *Factory) NewValidation() *Builder {
+	return f.New(Validation)
+}
+
+// NewUnavailable creates an error builder for Unavailable errors
+func (f *Factory) NewUnavailable() *Builder {
+	return f.New(Unavailable)
+}
+
+// NewTooManyRequests creates an error builder for TooManyRequests errors
+func (f *Factory) NewTooManyRequests() *Builder {
+	return f.New(TooManyRequests)
+}
+
+// NewNotImplemented creates an error builder for NotImplemented errors
+func (f *Factory) NewNotImplemented() *Builder {
+	return f.New(NotImplemented)
+}
+
+// NewCancelled creates an error builder for Cancelled errors
+func (f *Factory) NewCancelled() *Builder {
+	return f.New(Cancelled)
+}
+
+// NewPreconditionFailed creates an error builder for PreconditionFailed errors
+func (f *Factory) NewPreconditionFailed() *Builder {
+	return f.New(PreconditionFailed)
+}
+
+// NewResourceExhausted creates an error builder for ResourceExhausted errors
+func (f *Factory) NewResourceExhausted() *Builder {
+	return f.New(ResourceExhausted)
+}
+
+// NewGone creates an error builder for Gone errors
+func (f *Factory) NewGone() *Builder {
+	return f.New(Gone)
+}
+
+// NewPayloadTooLarge creates an error builder for PayloadTooLarge errors
+func (f *Factory) NewPayloadTooLarge() *Builder {
+	return f.New(PayloadTooLarge)
+}
+
+// NewUnsupportedMediaType creates an error builder for UnsupportedMediaType errors
+func (f *Factory) NewUnsupportedMediaType() *Builder {
+	return f.New(UnsupportedMediaType)
+}
+
+// Wrap creates an Error in the namespace that wraps an existing error with the given code
+func (f *Factory) Wrap(err error, code Code, message string) *Error {
+	if err == nil {
+		return nil
+	}
+	return f.New(wrapCode(err, code)).WithMessage(message).WithCause(err).build(1)
+}
+
+// WrapIfErr wraps an error in the namespace only if it's not nil
+func (f *Factory) WrapIfErr(err error, code Code, message string) error {
+	if err == nil {
+		return nil
+	}
+	return f.New(wrapCode(err, code)).WithMessage(message).WithCause(err).build(1)
+}
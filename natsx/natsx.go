@@ -0,0 +1,144 @@
+// Package natsx propagates errx errors over NATS request-reply
+// A responder replies with the error's wire envelope as the payload and its
+// code in the headers, and the requester turns such a reply back into an
+// error, so codes survive the hop the same way they do over HTTP and gRPC
+//
+//	nc.Subscribe("users.get", natsx.Handler(func(msg *nats.Msg) ([]byte, error) {
+//		return getUser(msg.Data)
+//	}))
+//
+//	reply, err := natsx.Request(ctx, nc, "users.get", id)
+//	if errx.IsCode(err, errx.NotFound) { ... }
+package natsx
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nordew/go-errx"
+)
+
+// Headers of the NATS services framework (nats.go/micro), set on error
+// replies so micro clients see the failure too
+const (
+	ServiceErrorHeader     = "Nats-Service-Error"
+	ServiceErrorCodeHeader = "Nats-Service-Error-Code"
+)
+
+// NewErrorMsg builds a reply message carrying err: the payload is its wire
+// envelope and the headers hold the code, ID and content type, along with
+// the message and HTTP status as the micro framework expects them
+// The installed sanitizer is applied; the subject is set when the message is
+// sent with Msg.RespondMsg
+func NewErrorMsg(err error) *nats.Msg {
+	msg := nats.NewMsg("")
+	data, encErr := errx.EncodeWire(err)
+	if encErr != nil {
+		// Fields that can't be encoded still leave the code and message
+		data, _ = errx.EncodeWire(errx.New(errx.GetCode(err)).Unreported().WithMessage(errx.GetMessage(err)).Build())
+	}
+	msg.Data = data
+
+	msg.Header.Set(errx.CodeHeader, string(errx.GetCode(err)))
+	if id := errx.GetID(err); id != "" {
+		msg.Header.Set(errx.IDHeader, id)
+	}
+	msg.Header.Set("Content-Type", errx.WireContentType)
+	msg.Header.Set(ServiceErrorHeader, errx.Sanitize(errx.GetMessage(err)))
+	msg.Header.Set(ServiceErrorCodeHeader, strconv.Itoa(errx.HTTPStatus(err)))
+	return msg
+}
+
+// RespondError replies to a request with NewErrorMsg(err)
+func RespondError(msg *nats.Msg, err error) error {
+	return msg.RespondMsg(NewErrorMsg(err))
+}
+
+// Handler adapts a function returning a reply payload or an error into a
+// message handler: the payload is sent as the reply, an error with
+// RespondError, and a panic is converted with errx.FromPanic and sent as an
+// error
+// Errors sending the reply are dropped, as NATS handlers can't return them
+func Handler(fn func(msg *nats.Msg) ([]byte, error)) nats.MsgHandler {
+	return func(msg *nats.Msg) {
+		data, err := call(fn, msg)
+		if err != nil {
+			RespondError(msg, err)
+			return
+		}
+		msg.Respond(data)
+	}
+}
+
+// call runs fn, converting a panic into its error
+func call(fn func(msg *nats.Msg) ([]byte, error), msg *nats.Msg) (data []byte, err error) {
+	defer errx.Recover(&err)
+	return fn(msg)
+}
+
+// IsError reports whether a reply carries an error sent by NewErrorMsg
+func IsError(msg *nats.Msg) bool {
+	return msg != nil && msg.Header.Get(errx.CodeHeader) != ""
+}
+
+// FromMsg returns the error carried by a reply, or nil if it isn't an error
+// reply
+// A payload that isn't a wire envelope still yields an error with the code
+// from the headers and the micro error message
+func FromMsg(msg *nats.Msg) error {
+	if !IsError(msg) {
+		return nil
+	}
+	if remote, err := errx.DecodeWire(msg.Data); err == nil {
+		return remote
+	}
+	return &errx.RemoteError{
+		Code:    errx.Code(msg.Header.Get(errx.CodeHeader)),
+		ID:      msg.Header.Get(errx.IDHeader),
+		Message: msg.Header.Get(ServiceErrorHeader),
+	}
+}
+
+// Request sends a request and waits for the reply like
+// nats.Conn.RequestWithContext, returning the error carried by an error
+// reply instead of the reply itself
+// Transport failures are converted with FromError
+func Request(ctx context.Context, nc *nats.Conn, subject string, data []byte) (*nats.Msg, error) {
+	msg, err := nc.RequestWithContext(ctx, subject, data)
+	if err != nil {
+		return nil, FromError(err)
+	}
+	if err := FromMsg(msg); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// FromError converts a NATS client error into an Error wrapping it: no
+// responders and a closed or draining connection become Unavailable, and
+// timeouts and context errors become Timeout or Cancelled
+// Returns nil for a nil error and errx errors unchanged
+func FromError(err error) error {
+	if err == nil {
+		return nil
+	}
+	switch err.(type) {
+	case *errx.Error, *errx.RemoteError:
+		return err
+	}
+
+	code := errx.Internal
+	switch {
+	case errors.Is(err, nats.ErrNoResponders),
+		errors.Is(err, nats.ErrConnectionClosed),
+		errors.Is(err, nats.ErrConnectionDraining),
+		errors.Is(err, nats.ErrDisconnected):
+		code = errx.Unavailable
+	case errors.Is(err, nats.ErrTimeout):
+		code = errx.Timeout
+	}
+	// Wrap maps context errors wrapped with Internal to Cancelled and Timeout
+	return errx.WrapSkip(1, err, code, "nats request failed")
+}
@@ -0,0 +1,39 @@
+package errx
+
+import "hash/fnv"
+
+// CodeNumeric returns the stable numeric identifier of the given code
+// Standard codes use four-digit numbers based on their HTTP status; codes
+// registered without a number, and unregistered codes, get a six-digit
+// number derived from a hash of the code so it never changes between releases
+func CodeNumeric(code Code) int {
+	if info, ok := LookupCode(code); ok && info.Numeric != 0 {
+		return info.Numeric
+	}
+	if code == "" {
+		return 0
+	}
+
+	h := fnv.New32a()
+	h.Write([]byte(code))
+	return 100000 + int(h.Sum32()%900000)
+}
+
+// NumericCode returns the numeric identifier of the error's code
+// Returns 0 for a nil error
+func NumericCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	return CodeNumeric(GetCode(err))
+}
+
+// CodeFromNumeric returns the registered code with the given numeric identifier
+func CodeFromNumeric(n int) (Code, bool) {
+	for _, info := range RegisteredCodes() {
+		if CodeNumeric(info.Code) == n {
+			return info.Code, true
+		}
+	}
+	return "", false
+}
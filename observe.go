@@ -0,0 +1,40 @@
+package errx
+
+import "sync"
+
+var (
+	observersMu sync.RWMutex
+	observers   []*func(*Error)
+)
+
+// Observe registers fn to be called with every Error created by Build or Wrap
+// The returned function unregisters it
+// Observers run synchronously on the creating goroutine, after the hooks
+// registered with OnBuild and OnWrap, and must not modify the error
+func Observe(fn func(*Error)) (remove func()) {
+	entry := &fn
+
+	observersMu.Lock()
+	observers = append(observers, entry)
+	observersMu.Unlock()
+
+	return func() {
+		observersMu.Lock()
+		defer observersMu.Unlock()
+		for i, o := range observers {
+			if o == entry {
+				observers = append(observers[:i:i], observers[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// notify passes a newly created error to the registered observers
+func notify(e *Error) {
+	observersMu.RLock()
+	defer observersMu.RUnlock()
+	for _, fn := range observers {
+		(*fn)(e)
+	}
+}
@@ -0,0 +1,21 @@
+package errx
+
+import (
+	"errors"
+	"slices"
+)
+
+// Ops returns the operations recorded with WithOp along the wrap chain,
+// innermost first, so they read in the order the error flowed,
+// e.g. ["userrepo.GetUser", "usersvc.Process"]
+func Ops(err error) []string {
+	var ops []string
+	for cause := err; cause != nil; cause = errors.Unwrap(cause) {
+		if e, ok := cause.(*Error); ok && e.op != "" {
+			ops = append(ops, e.op)
+		}
+	}
+
+	slices.Reverse(ops)
+	return ops
+}
@@ -0,0 +1,195 @@
+// Package openapi generates OpenAPI 3 components describing the error
+// responses written by httpx
+package openapi
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/nordew/go-errx"
+)
+
+// Schema is an OpenAPI schema object
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+}
+
+// Header is an OpenAPI header object
+type Header struct {
+	Description string  `json:"description,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+// MediaType is an OpenAPI media type object
+type MediaType struct {
+	Schema  *Schema `json:"schema"`
+	Example any     `json:"example,omitempty"`
+}
+
+// Response is an OpenAPI response object
+type Response struct {
+	Description string               `json:"description"`
+	Headers     map[string]*Header   `json:"headers,omitempty"`
+	Content     map[string]MediaType `json:"content"`
+}
+
+// Components holds the schemas and responses to merge into an OpenAPI
+// document's components
+type Components struct {
+	Schemas   map[string]*Schema   `json:"schemas"`
+	Responses map[string]*Response `json:"responses"`
+}
+
+// Schema and response names used in the generated components
+const (
+	ErrorSchema      = "ErrxError"
+	FieldErrorSchema = "ErrxFieldError"
+)
+
+// Option configures Generate
+type Option func(*options)
+
+type options struct {
+	codes []errx.Code
+}
+
+// WithCodes limits the generated responses to the given codes instead of
+// every registered code
+func WithCodes(codes ...errx.Code) Option {
+	return func(o *options) {
+		o.codes = codes
+	}
+}
+
+// Generate returns components with the error body schema and one response per
+// HTTP status used by the codes, named ResponseName(status)
+func Generate(opts ...Option) *Components {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.codes == nil {
+		for _, info := range errx.RegisteredCodes() {
+			o.codes = append(o.codes, info.Code)
+		}
+	}
+
+	byStatus := map[int][]errx.Code{}
+	for _, code := range o.codes {
+		status := errx.CodeHTTPStatus(code)
+		byStatus[status] = append(byStatus[status], code)
+	}
+
+	c := &Components{
+		Schemas: map[string]*Schema{
+			ErrorSchema:      errorSchema(o.codes),
+			FieldErrorSchema: fieldErrorSchema(),
+		},
+		Responses: make(map[string]*Response, len(byStatus)),
+	}
+	for status, codes := range byStatus {
+		c.Responses[ResponseName(status)] = response(status, codes)
+	}
+	return c
+}
+
+// WriteJSON writes the generated components to w as indented JSON
+func WriteJSON(w io.Writer, opts ...Option) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(Generate(opts...))
+}
+
+// ResponseName returns the name of the response component for a status,
+// e.g. "Error404"
+func ResponseName(status int) string {
+	return "Error" + strconv.Itoa(status)
+}
+
+// ResponseRef returns the reference to the response component for a status
+func ResponseRef(status int) string {
+	return "#/components/responses/" + ResponseName(status)
+}
+
+// errorSchema describes httpx.Response
+func errorSchema(codes []errx.Code) *Schema {
+	return &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"code":         {Type: "string", Description: "Error classification code", Enum: codeStrings(codes)},
+			"id":           {Type: "string", Description: "Identifier of this occurrence of the error"},
+			"numeric_code": {Type: "integer", Description: "Stable numeric identifier of the code"},
+			"message":      {Type: "string", Description: "User-friendly error message"},
+			"fields":       {Type: "object", Description: "Structured context", AdditionalProperties: &Schema{}},
+			"field_errors": {Type: "array", Items: &Schema{Ref: "#/components/schemas/" + FieldErrorSchema}},
+		},
+		Required: []string{"code", "numeric_code", "message"},
+	}
+}
+
+// fieldErrorSchema describes errx.FieldError
+func fieldErrorSchema() *Schema {
+	return &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"field":   {Type: "string", Description: "Name or path of the invalid field"},
+			"rule":    {Type: "string", Description: "Validation rule that failed"},
+			"message": {Type: "string", Description: "User-friendly description of the problem"},
+			"value":   {Description: "Rejected value"},
+		},
+		Required: []string{"field", "message"},
+	}
+}
+
+// response describes the error response for a status used by codes
+func response(status int, codes []errx.Code) *Response {
+	slices.Sort(codes)
+
+	// httpx hides the messages of server errors behind the status text
+	message := http.StatusText(status)
+	if status < http.StatusInternalServerError {
+		message = errx.DefaultMessage(codes[0])
+	}
+
+	desc := http.StatusText(status)
+	if desc == "" {
+		desc = "Error"
+	}
+	return &Response{
+		Description: desc,
+		Headers: map[string]*Header{
+			errx.CodeHeader: {Description: "Error classification code", Schema: &Schema{Type: "string", Enum: codeStrings(codes)}},
+			errx.IDHeader:   {Description: "Identifier of this occurrence of the error", Schema: &Schema{Type: "string"}},
+		},
+		Content: map[string]MediaType{
+			"application/json": {
+				Schema: &Schema{Ref: "#/components/schemas/" + ErrorSchema},
+				Example: map[string]any{
+					"code":         codes[0],
+					"numeric_code": errx.CodeNumeric(codes[0]),
+					"message":      message,
+				},
+			},
+		},
+	}
+}
+
+// codeStrings returns the sorted, distinct codes as strings
+func codeStrings(codes []errx.Code) []string {
+	s := make([]string, 0, len(codes))
+	for _, code := range codes {
+		s = append(s, string(code))
+	}
+	slices.Sort(s)
+	return slices.Compact(s)
+}
@@ -0,0 +1,78 @@
+package errx
+
+// Option configures an error built with NewE
+// Options are applied to a Builder, so helpers can compose any builder method:
+//
+//	func withUser(id int) errx.Option {
+//		return func(b *errx.Builder) { b.WithField("user_id", id) }
+//	}
+type Option func(*Builder)
+
+// NewE builds an Error with the given code in a single expression, applying
+// the options in order
+//
+//	err := errx.NewE(errx.NotFound, errx.WithMsgf("user %d not found", id), errx.WithCauseOpt(dbErr))
+func NewE(code Code, opts ...Option) *Error {
+	b := New(code)
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b.build(1)
+}
+
+// WithMsg sets the message, as Builder.WithMessage does
+func WithMsg(msg string) Option {
+	return func(b *Builder) {
+		b.WithMessage(msg)
+	}
+}
+
+// WithMsgf sets a formatted message, as Builder.WithMessagef does
+func WithMsgf(format string, args ...any) Option {
+	return func(b *Builder) {
+		b.WithMessagef(format, args...)
+	}
+}
+
+// WithCauseOpt sets the underlying cause, as Builder.WithCause does
+func WithCauseOpt(err error) Option {
+	return func(b *Builder) {
+		b.WithCause(err)
+	}
+}
+
+// WithFieldOpt adds a field, as Builder.WithField does
+func WithFieldOpt(key string, value any) Option {
+	return func(b *Builder) {
+		b.WithField(key, value)
+	}
+}
+
+// WithFieldsOpt adds several fields, as Builder.WithFields does
+func WithFieldsOpt(fields map[string]any) Option {
+	return func(b *Builder) {
+		b.WithFields(fields)
+	}
+}
+
+// WithDetailsOpt merges details into the error's details, as
+// Builder.WithDetails does
+func WithDetailsOpt(details map[string]any) Option {
+	return func(b *Builder) {
+		b.WithDetails(details)
+	}
+}
+
+// WithOpOpt records the failed operation, as Builder.WithOp does
+func WithOpOpt(op string) Option {
+	return func(b *Builder) {
+		b.WithOp(op)
+	}
+}
+
+// WithStackOpt captures the call stack, as Builder.WithStack does
+func WithStackOpt() Option {
+	return func(b *Builder) {
+		b.WithStack()
+	}
+}
@@ -0,0 +1,71 @@
+package errx
+
+import "fmt"
+
+// PanicField is the field key holding a recovered panic value
+// PublicFields drops it, so the value is logged but never sent to clients
+const PanicField = "panic"
+
+// FromPanic converts a value recovered from a panic into an Internal error
+// with a new ID, the value as a field and the stack of the panicking
+// goroutine; a value that is an error becomes the cause
+// A panic raised by Try or Try1 returns the error it was given instead
+// It must be called from the deferred function that recovered the panic
+func FromPanic(r any) *Error {
+	if t, ok := r.(tryPanic); ok {
+		return t.err
+	}
+	cause, ok := r.(error)
+	if !ok {
+		cause = fmt.Errorf("%v", r)
+	}
+	return NewInternal().
+		WithMessage("panic recovered").
+		WithCause(cause).
+		WithField(PanicField, fmt.Sprint(r)).
+		WithID(NewID()).
+		WithStack().
+		build(1)
+}
+
+// recovered reports whether e was created by FromPanic from a panic value
+func recovered(e *Error) bool {
+	_, ok := e.Fields[PanicField]
+	return ok
+}
+
+// Recover converts a panic of the calling function into an error stored in
+// *errp, as FromPanic converts it, so a crashing worker returns an error
+// instead of taking the process down; the panic replaces any error already
+// stored
+// It must be deferred directly by a function with a named error result:
+//
+//	func work() (err error) {
+//		defer errx.Recover(&err)
+//		...
+//	}
+func Recover(errp *error) {
+	if r := recover(); r != nil {
+		*errp = FromPanic(r)
+	}
+}
+
+// Go runs fn in a new goroutine and delivers its error, or the error
+// converted from its panic by Recover, on the returned channel, which is
+// closed afterwards; a nil error is delivered too, so receiving waits for fn
+// to finish
+// The channel is buffered, so fn's goroutine doesn't leak if nobody receives
+func Go(fn func() error) <-chan error {
+	ch := make(chan error, 1)
+	go func() {
+		defer close(ch)
+		ch <- run(fn)
+	}()
+	return ch
+}
+
+// run calls fn, converting a panic into its error
+func run(fn func() error) (err error) {
+	defer Recover(&err)
+	return fn()
+}
@@ -0,0 +1,18 @@
+module github.com/nordew/go-errx/postgresx
+
+go 1.24.1
+
+require (
+	github.com/jackc/pgx/v5 v5.7.5
+	github.com/lib/pq v1.10.9
+	github.com/nordew/go-errx v0.0.0
+)
+
+require (
+	github.com/jackc/pgpassfile v1.0.0 // indirect
+	github.com/jackc/pgservicefile v0.0.0-20240606120523-5a60cdf6a761 // indirect
+	golang.org/x/crypto v0.37.0 // indirect
+	golang.org/x/text v0.24.0 // indirect
+)
+
+replace github.com/nordew/go-errx => ../
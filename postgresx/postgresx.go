@@ -0,0 +1,112 @@
+// Package postgresx maps PostgreSQL errors from pgx and lib/pq to errx errors
+package postgresx
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/lib/pq"
+	"github.com/nordew/go-errx"
+	"github.com/nordew/go-errx/sqlx"
+)
+
+// Field keys used for the details of a PostgreSQL error
+const (
+	SQLStateField   = "sqlstate"
+	ConstraintField = "constraint"
+	TableField      = "table"
+	ColumnField     = "column"
+)
+
+// pgError holds the details shared by pgx and lib/pq errors
+type pgError struct {
+	code       string
+	constraint string
+	table      string
+	column     string
+}
+
+// MapError wraps a PostgreSQL error in an Error with a code derived from its
+// SQLSTATE, attaching the SQLSTATE and the constraint, table and column names
+// as fields
+// unique_violation maps to AlreadyExists, foreign_key_violation to Conflict,
+// query_canceled to Cancelled and serialization failures and deadlocks to a
+// retryable Conflict; other errors are mapped by sqlx.Code
+// Errors that already carry an errx code are returned unchanged
+// Returns nil for a nil error
+func MapError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var e *errx.Error
+	if errors.As(err, &e) {
+		return err
+	}
+
+	pg, ok := asPgError(err)
+	if !ok {
+		return errx.WrapSkip(1, err, sqlx.Code(err), "")
+	}
+
+	b := errx.New(Code(pg.code)).WithCause(err).WithCallerSkip(1)
+	b.WithField(SQLStateField, pg.code)
+	if pg.constraint != "" {
+		b.WithField(ConstraintField, pg.constraint)
+	}
+	if pg.table != "" {
+		b.WithField(TableField, pg.table)
+	}
+	if pg.column != "" {
+		b.WithField(ColumnField, pg.column)
+	}
+	switch pg.code {
+	case "40001", "40P01":
+		b.WithRetryable(true)
+	}
+	return b.Build()
+}
+
+// Code returns the errx code describing a SQLSTATE error code
+// Returns Internal if the state has no mapping
+func Code(state string) errx.Code {
+	switch state {
+	case "23505": // unique_violation
+		return errx.AlreadyExists
+	case "23503": // foreign_key_violation
+		return errx.Conflict
+	case "57014": // query_canceled
+		return errx.Cancelled
+	case "40001", "40P01": // serialization_failure, deadlock_detected
+		return errx.Conflict
+	}
+
+	if code, ok := sqlx.CodeFromSQLState(state); ok {
+		return code
+	}
+	return errx.Internal
+}
+
+// asPgError extracts the details of a pgx or lib/pq error in err's chain
+func asPgError(err error) (pgError, bool) {
+	var pgxErr *pgconn.PgError
+	if errors.As(err, &pgxErr) {
+		return pgError{
+			code:       pgxErr.Code,
+			constraint: pgxErr.ConstraintName,
+			table:      pgxErr.TableName,
+			column:     pgxErr.ColumnName,
+		}, true
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pgError{
+			code:       string(pqErr.Code),
+			constraint: pqErr.Constraint,
+			table:      pqErr.Table,
+			column:     pqErr.Column,
+		}, true
+	}
+	return pgError{}, false
+}
@@ -0,0 +1,128 @@
+package errx
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+)
+
+// ANSI escape sequences used by Pretty
+const (
+	ansiReset  = "\x1b[0m"
+	ansiBold   = "\x1b[1m"
+	ansiDim    = "\x1b[2m"
+	ansiRed    = "\x1b[31m"
+	ansiYellow = "\x1b[33m"
+	ansiBlue   = "\x1b[34m"
+	ansiCyan   = "\x1b[36m"
+)
+
+// Pretty returns a multi-line rendering of an error for terminals: the code
+// colored by severity, the message, operation, fields in key order and stack
+// frames or call site of each layer, with causes indented below the layer
+// wrapping them and every member of a joined error listed
+// Colors are omitted if the NO_COLOR environment variable is set
+// Returns an empty string for a nil error
+func Pretty(err error) string {
+	if err == nil {
+		return ""
+	}
+	p := &pretty{color: os.Getenv("NO_COLOR") == ""}
+	p.write(err, 0)
+	return strings.TrimSuffix(p.b.String(), "\n")
+}
+
+// pretty accumulates the output of Pretty
+type pretty struct {
+	b     strings.Builder
+	color bool
+}
+
+// paint wraps s in the escape sequence if colors are enabled
+func (p *pretty) paint(style, s string) string {
+	if !p.color || s == "" {
+		return s
+	}
+	return style + s + ansiReset
+}
+
+// line writes one indented line
+func (p *pretty) line(depth int, format string, args ...any) {
+	p.b.WriteString(strings.Repeat("  ", depth))
+	fmt.Fprintf(&p.b, format, args...)
+	p.b.WriteByte('\n')
+}
+
+// write renders err and its causes at the given depth
+func (p *pretty) write(err error, depth int) {
+	e, ok := err.(*Error)
+	if !ok {
+		p.line(depth, "%s %s", p.paint(ansiDim, fmt.Sprintf("%T", err)), err.Error())
+		if joined, ok := err.(interface{ Unwrap() []error }); ok {
+			p.causes(joined.Unwrap(), depth)
+		} else if cause := unwrapOne(err); cause != nil {
+			p.causes([]error{cause}, depth)
+		}
+		return
+	}
+
+	p.line(depth, "%s %s", p.paint(ansiBold+severityColor(GetSeverity(e)), string(e.Code)), p.paint(ansiBold, e.Message))
+	if e.op != "" {
+		p.line(depth+1, "%s %s", p.paint(ansiCyan, "op:"), e.op)
+	}
+	for _, key := range slices.Sorted(maps.Keys(e.Fields)) {
+		p.line(depth+1, "%s %v", p.paint(ansiCyan, key+":"), e.Fields[key])
+	}
+	if frames := e.StackTrace(); len(frames) > 0 {
+		p.line(depth+1, "%s", p.paint(ansiCyan, "stack:"))
+		for _, frame := range frames {
+			p.line(depth+2, "%s", p.paint(ansiDim, fmt.Sprintf("%s %s:%d", frame.Function, frame.File, frame.Line)))
+		}
+	} else if src := e.Source(); src.File != "" {
+		p.line(depth+1, "%s %s", p.paint(ansiCyan, "at:"), p.paint(ansiDim, src.String()))
+	}
+
+	if e.Err == nil {
+		return
+	}
+	if joined, ok := e.Err.(interface{ Unwrap() []error }); ok {
+		p.causes(joined.Unwrap(), depth)
+		return
+	}
+	p.causes([]error{e.Err}, depth)
+}
+
+// causes renders the causes of a layer under a "caused by" heading
+func (p *pretty) causes(errs []error, depth int) {
+	if len(errs) == 0 {
+		return
+	}
+	p.line(depth+1, "%s", p.paint(ansiDim, "caused by:"))
+	for _, cause := range errs {
+		if cause != nil {
+			p.write(cause, depth+2)
+		}
+	}
+}
+
+// unwrapOne returns the single error wrapped by err, if any
+func unwrapOne(err error) error {
+	u, ok := err.(interface{ Unwrap() error })
+	if !ok {
+		return nil
+	}
+	return u.Unwrap()
+}
+
+// severityColor returns the color of a code in Pretty output
+func severityColor(s Severity) string {
+	switch s {
+	case SeverityInfo:
+		return ansiBlue
+	case SeverityWarning:
+		return ansiYellow
+	}
+	return ansiRed
+}
@@ -0,0 +1,78 @@
+package errx
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// ProblemContentType is the media type of RFC 7807 Problem Details bodies
+const ProblemContentType = "application/problem+json"
+
+// Problem is an RFC 7807 Problem Details object
+// Code, NumericCode, Fields and FieldErrors are serialized as extension members
+type Problem struct {
+	Type        string         `json:"type"`
+	Title       string         `json:"title"`
+	Status      int            `json:"status"`
+	Detail      string         `json:"detail,omitempty"`
+	Instance    string         `json:"instance,omitempty"`
+	Code        Code           `json:"code"`
+	NumericCode int            `json:"numeric_code"`
+	Fields      map[string]any `json:"fields,omitempty"`
+	FieldErrors []FieldError   `json:"field_errors,omitempty"`
+}
+
+// ToProblem converts an error into a Problem Details object
+// Only messages of Error values are exposed as the detail, so the text of
+// arbitrary wrapped errors never leaks to clients; the installed sanitizer is
+// applied to the detail, fields and field errors, and fields are filtered by
+// PublicFields
+// Server errors (5xx) only carry the status text as the detail, as
+// httpx.NewResponse reports them, so internal details don't leak to clients
+// The type is the URL registered for the code with WithDocURL, if any
+func ToProblem(err error) Problem {
+	status := HTTPStatus(err)
+	problem := Problem{
+		Type:        problemType(GetCode(err)),
+		Title:       http.StatusText(status),
+		Status:      status,
+		Code:        GetCode(err),
+		NumericCode: NumericCode(err),
+	}
+	if status >= http.StatusInternalServerError {
+		problem.Detail = http.StatusText(status)
+		return problem
+	}
+
+	var e *Error
+	if errors.As(err, &e) {
+		problem.Detail = Sanitize(e.Message)
+		problem.Fields = PublicFields(e.Fields)
+	}
+	problem.FieldErrors = SanitizeFieldErrors(FieldErrors(err))
+	return problem
+}
+
+// problemType returns the documentation URL registered for the code, or
+// "about:blank" if it has none
+func problemType(code Code) string {
+	if info, ok := LookupCode(code); ok && info.DocURL != "" {
+		return info.DocURL
+	}
+	return "about:blank"
+}
+
+// WriteProblem writes the error to w as an application/problem+json response
+// The code and ID are also sent as the X-Errx-Code and X-Errx-Id headers
+func WriteProblem(w http.ResponseWriter, err error) error {
+	problem := ToProblem(err)
+
+	w.Header().Set(CodeHeader, string(problem.Code))
+	if id := GetID(err); id != "" {
+		w.Header().Set(IDHeader, id)
+	}
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.WriteHeader(problem.Status)
+	return json.NewEncoder(w).Encode(problem)
+}
@@ -0,0 +1,38 @@
+package errx
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestToProblem(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		status     int
+		detail     string
+		wantFields bool
+	}{
+		{"client error", New(NotFound).WithMessage("user not found").WithField("user_id", 7).Error(), http.StatusNotFound, "user not found", true},
+		{"internal", New(Internal).WithMessage("query failed: password=hunter2").WithField("dsn", "postgres://db").Error(), http.StatusInternalServerError, "Internal Server Error", false},
+		{"panic", FromPanic("nil map write"), http.StatusInternalServerError, "Internal Server Error", false},
+		{"unavailable", New(Unavailable).WithMessage("pool exhausted").Error(), http.StatusServiceUnavailable, "Service Unavailable", false},
+		{"plain error", errors.New("dial tcp 10.0.0.5: refused"), http.StatusInternalServerError, "Internal Server Error", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := ToProblem(tt.err)
+			if p.Status != tt.status || p.Detail != tt.detail {
+				t.Errorf("ToProblem = %d %q, want %d %q", p.Status, p.Detail, tt.status, tt.detail)
+			}
+			if got := len(p.Fields) > 0; got != tt.wantFields {
+				t.Errorf("fields = %v, want present %t", p.Fields, tt.wantFields)
+			}
+			if p.Code != GetCode(tt.err) {
+				t.Errorf("code = %s, want %s", p.Code, GetCode(tt.err))
+			}
+		})
+	}
+}
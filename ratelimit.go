@@ -0,0 +1,74 @@
+package errx
+
+import "time"
+
+// Field names used by WithRateLimit
+const (
+	RateLimitField     = "rate_limit"
+	RateRemainingField = "rate_remaining"
+	RateResetField     = "rate_reset"
+)
+
+// NewRateLimited creates a TooManyRequests Builder carrying the quota that
+// was exceeded, as WithRateLimit records it
+func NewRateLimited(limit, remaining int, reset time.Time) *Builder {
+	return NewTooManyRequests().WithRateLimit(limit, remaining, reset)
+}
+
+// WithRateLimit records the request quota of the client: the number of
+// requests allowed per window, how many are left and when the window resets
+// The values are stored as the rate_limit, rate_remaining and rate_reset
+// fields; httpx sends them as X-RateLimit-* headers, and RetryAfter falls
+// back to the time left until the reset
+func (b *Builder) WithRateLimit(limit, remaining int, reset time.Time) *Builder {
+	b.WithField(RateLimitField, limit).WithField(RateRemainingField, remaining)
+	if !reset.IsZero() {
+		b.WithField(RateResetField, reset)
+	}
+	return b
+}
+
+// RateLimit returns the quota recorded with WithRateLimit on the outermost
+// Error in the chain having one
+// Values restored from JSON are converted back; a reset that wasn't
+// recorded is returned as the zero time
+func RateLimit(err error) (limit, remaining int, reset time.Time, ok bool) {
+	for e := range ChainErrx(err) {
+		if limit, ok := intField(e.Fields[RateLimitField]); ok {
+			remaining, _ := intField(e.Fields[RateRemainingField])
+			reset, _ := timeField(e.Fields[RateResetField])
+			return limit, remaining, reset, true
+		}
+	}
+	return 0, 0, time.Time{}, false
+}
+
+// Limit returns the number of requests allowed per window recorded with
+// WithRateLimit
+func (e *TooManyRequestsError) Limit() (int, bool) {
+	return intField(e.Fields[RateLimitField])
+}
+
+// Remaining returns the number of requests left recorded with WithRateLimit
+func (e *TooManyRequestsError) Remaining() (int, bool) {
+	return intField(e.Fields[RateRemainingField])
+}
+
+// Reset returns when the quota window resets, recorded with WithRateLimit
+func (e *TooManyRequestsError) Reset() (time.Time, bool) {
+	return timeField(e.Fields[RateResetField])
+}
+
+// intField converts a field value holding an integer, possibly decoded from
+// JSON as a float
+func intField(v any) (int, bool) {
+	switch v := v.(type) {
+	case int:
+		return v, true
+	case int64:
+		return int(v), true
+	case float64:
+		return int(v), true
+	}
+	return 0, false
+}
@@ -0,0 +1,98 @@
+package errx
+
+import (
+	"sync"
+	"time"
+)
+
+// RecentError is a snapshot of an error kept in the recent error buffer
+type RecentError struct {
+	Code        Code           `json:"code"`
+	Message     string         `json:"message"` // Text of the error, including its causes
+	Fields      map[string]any `json:"fields,omitempty"`
+	Time        time.Time      `json:"time"`
+	Fingerprint string         `json:"fingerprint"`
+	ID          string         `json:"id,omitempty"`
+}
+
+// ring is a bounded buffer of recent errors, overwriting the oldest when full
+type ring struct {
+	mu      sync.Mutex
+	entries []RecentError
+	next    int  // Index of the slot written next
+	full    bool // Whether every slot holds an entry
+}
+
+var recentErrors = &ring{}
+
+// WithRecentErrors keeps the last size errors created by Build or Wrap in
+// memory for RecentErrors, for live debugging without access to logs
+// Messages and fields go through the installed sanitizer when recorded
+// A size of 0 disables the buffer, which is the default; changing the size
+// discards the recorded errors
+func WithRecentErrors(size int) ConfigOption {
+	return func() {
+		r := recentErrors
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.entries = make([]RecentError, max(size, 0))
+		r.next, r.full = 0, false
+	}
+}
+
+// RecentErrors returns the errors recorded by the buffer enabled with
+// WithRecentErrors, most recent first
+// Returns nil if the buffer is disabled
+func RecentErrors() []RecentError {
+	r := recentErrors
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	n := r.next
+	if r.full {
+		n = len(r.entries)
+	}
+	if n == 0 {
+		return nil
+	}
+
+	recent := make([]RecentError, 0, n)
+	for i := 1; i <= n; i++ {
+		recent = append(recent, r.entries[(r.next-i+len(r.entries))%len(r.entries)])
+	}
+	return recent
+}
+
+// record adds a newly created error to the buffer if it's enabled
+func (r *ring) record(e *Error) {
+	r.mu.Lock()
+	enabled := len(r.entries) > 0
+	r.mu.Unlock()
+	if !enabled {
+		return
+	}
+
+	t := e.time
+	if t.IsZero() {
+		t = currentTime()
+	}
+	entry := RecentError{
+		Code:        e.Code,
+		Message:     Sanitize(e.Error()),
+		Fields:      SanitizeFields(e.Fields),
+		Time:        t,
+		Fingerprint: Fingerprint(e),
+		ID:          e.id,
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.entries) == 0 {
+		return
+	}
+	r.entries[r.next] = entry
+	r.next = (r.next + 1) % len(r.entries)
+	if r.next == 0 {
+		r.full = true
+	}
+}
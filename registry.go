@@ -0,0 +1,386 @@
+package errx
+
+import (
+	"net/http"
+	"sync"
+)
+
+// CodeInfo describes how errors with a code behave in mapping helpers
+type CodeInfo struct {
+	Code        Code          // The described code
+	Numeric     int           // Stable numeric identifier, derived from the code if unset
+	HTTPStatus  int           // HTTP status, 500 if unset
+	GRPCCode    uint32        // gRPC status code (google.golang.org/grpc/codes), Internal if unset
+	Severity    Severity      // Default severity, SeverityError if unset
+	Retryable   bool          // Whether errors are retryable by default
+	Categories  Category      // Coarse-grained classes, derived from the HTTP status and retryability if unset
+	Description string        // Human-readable description
+	Message     string        // Default message for errors built without one
+	DocURL      string        // Documentation of the code, used as the Problem Details type
+	Disposition MessageAction // Action taken by message consumers, classified by Disposition if unset
+}
+
+// CodeOption configures a registered code
+type CodeOption func(*CodeInfo)
+
+// WithNumericCode sets the stable numeric identifier of the code
+func WithNumericCode(n int) CodeOption {
+	return func(info *CodeInfo) {
+		info.Numeric = n
+	}
+}
+
+// WithHTTPStatus sets the HTTP status used for the code
+func WithHTTPStatus(status int) CodeOption {
+	return func(info *CodeInfo) {
+		info.HTTPStatus = status
+	}
+}
+
+// WithGRPCCode sets the gRPC status code used for the code
+// Pass a google.golang.org/grpc/codes value converted to uint32
+func WithGRPCCode(code uint32) CodeOption {
+	return func(info *CodeInfo) {
+		info.GRPCCode = code
+	}
+}
+
+// WithDefaultSeverity sets the severity used when an error doesn't set one
+func WithDefaultSeverity(s Severity) CodeOption {
+	return func(info *CodeInfo) {
+		info.Severity = s
+	}
+}
+
+// WithDefaultRetryable sets whether errors are retryable when they don't say otherwise
+func WithDefaultRetryable(retryable bool) CodeOption {
+	return func(info *CodeInfo) {
+		info.Retryable = retryable
+	}
+}
+
+// WithCategories sets the categories the code belongs to
+func WithCategories(c Category) CodeOption {
+	return func(info *CodeInfo) {
+		info.Categories = c
+	}
+}
+
+// WithCodeDescription sets the human-readable description of the code
+func WithCodeDescription(desc string) CodeOption {
+	return func(info *CodeInfo) {
+		info.Description = desc
+	}
+}
+
+// WithDefaultMessage sets the message used for errors built without one
+func WithDefaultMessage(msg string) CodeOption {
+	return func(info *CodeInfo) {
+		info.Message = msg
+	}
+}
+
+// WithDocURL sets the URL documenting the code
+func WithDocURL(url string) CodeOption {
+	return func(info *CodeInfo) {
+		info.DocURL = url
+	}
+}
+
+// gRPC status codes used by the standard codes, mirroring google.golang.org/grpc/codes
+const (
+	grpcCanceled           uint32 = 1
+	grpcInvalidArgument    uint32 = 3
+	grpcDeadlineExceeded   uint32 = 4
+	grpcNotFound           uint32 = 5
+	grpcAlreadyExists      uint32 = 6
+	grpcPermissionDenied   uint32 = 7
+	grpcResourceExhausted  uint32 = 8
+	grpcFailedPrecondition uint32 = 9
+	grpcAborted            uint32 = 10
+	grpcUnimplemented      uint32 = 12
+	grpcInternal           uint32 = 13
+	grpcUnavailable        uint32 = 14
+	grpcUnauthenticated    uint32 = 16
+)
+
+// standardCodes describes the codes registered by default
+var standardCodes = []CodeInfo{
+	{
+		Code:        BadRequest,
+		Numeric:     4000,
+		HTTPStatus:  http.StatusBadRequest,
+		GRPCCode:    grpcInvalidArgument,
+		Severity:    SeverityWarning,
+		Categories:  CategoryClient,
+		Description: "Invalid input or parameters",
+		Message:     "bad request",
+	},
+	{
+		Code:        Validation,
+		Numeric:     4220,
+		HTTPStatus:  http.StatusUnprocessableEntity,
+		GRPCCode:    grpcInvalidArgument,
+		Severity:    SeverityWarning,
+		Categories:  CategoryClient,
+		Description: "Input validation failed",
+		Message:     "validation failed",
+	},
+	{
+		Code:        Unauthorized,
+		Numeric:     4010,
+		HTTPStatus:  http.StatusUnauthorized,
+		GRPCCode:    grpcUnauthenticated,
+		Severity:    SeverityWarning,
+		Categories:  CategoryClient | CategorySecurity,
+		Description: "Authentication required",
+		Message:     "authentication required",
+	},
+	{
+		Code:        Forbidden,
+		Numeric:     4030,
+		HTTPStatus:  http.StatusForbidden,
+		GRPCCode:    grpcPermissionDenied,
+		Severity:    SeverityWarning,
+		Categories:  CategoryClient | CategorySecurity,
+		Description: "Permission denied",
+		Message:     "permission denied",
+	},
+	{
+		Code:        NotFound,
+		Numeric:     4040,
+		HTTPStatus:  http.StatusNotFound,
+		GRPCCode:    grpcNotFound,
+		Severity:    SeverityInfo,
+		Categories:  CategoryClient,
+		Description: "Resource not found",
+		Message:     "resource not found",
+	},
+	{
+		Code:        Conflict,
+		Numeric:     4090,
+		HTTPStatus:  http.StatusConflict,
+		GRPCCode:    grpcAborted,
+		Severity:    SeverityInfo,
+		Categories:  CategoryClient,
+		Description: "Resource conflicts with existing data",
+		Message:     "resource conflict",
+	},
+	{
+		Code:        AlreadyExists,
+		Numeric:     4091,
+		HTTPStatus:  http.StatusConflict,
+		GRPCCode:    grpcAlreadyExists,
+		Severity:    SeverityInfo,
+		Categories:  CategoryClient,
+		Description: "Resource already exists",
+		Message:     "resource already exists",
+	},
+	{
+		Code:        Timeout,
+		Numeric:     5040,
+		HTTPStatus:  http.StatusGatewayTimeout,
+		GRPCCode:    grpcDeadlineExceeded,
+		Severity:    SeverityError,
+		Retryable:   true,
+		Categories:  CategoryServer | CategoryTransient,
+		Description: "Operation timed out",
+		Message:     "operation timed out",
+	},
+	{
+		Code:        Internal,
+		Numeric:     5000,
+		HTTPStatus:  http.StatusInternalServerError,
+		GRPCCode:    grpcInternal,
+		Severity:    SeverityError,
+		Retryable:   true,
+		Categories:  CategoryServer,
+		Description: "Internal server or system errors",
+		Message:     "internal error",
+	},
+	{
+		Code:        Unavailable,
+		Numeric:     5030,
+		HTTPStatus:  http.StatusServiceUnavailable,
+		GRPCCode:    grpcUnavailable,
+		Severity:    SeverityError,
+		Retryable:   true,
+		Categories:  CategoryServer | CategoryTransient,
+		Description: "Service temporarily unavailable",
+		Message:     "service unavailable",
+	},
+	{
+		Code:        TooManyRequests,
+		Numeric:     4290,
+		HTTPStatus:  http.StatusTooManyRequests,
+		GRPCCode:    grpcResourceExhausted,
+		Severity:    SeverityWarning,
+		Retryable:   true,
+		Categories:  CategoryClient | CategoryTransient,
+		Description: "Rate limit exceeded",
+		Message:     "too many requests",
+	},
+	{
+		Code:        NotImplemented,
+		Numeric:     5010,
+		HTTPStatus:  http.StatusNotImplemented,
+		GRPCCode:    grpcUnimplemented,
+		Severity:    SeverityError,
+		Categories:  CategoryServer,
+		Description: "Operation not implemented",
+		Message:     "not implemented",
+	},
+	{
+		Code:        Cancelled,
+		Numeric:     4990,
+		HTTPStatus:  StatusClientClosedRequest,
+		GRPCCode:    grpcCanceled,
+		Severity:    SeverityInfo,
+		Categories:  CategoryClient,
+		Description: "Operation cancelled by the caller",
+		Message:     "operation cancelled",
+	},
+	{
+		Code:        PreconditionFailed,
+		Numeric:     4120,
+		HTTPStatus:  http.StatusPreconditionFailed,
+		GRPCCode:    grpcFailedPrecondition,
+		Severity:    SeverityWarning,
+		Categories:  CategoryClient,
+		Description: "System not in the state required by the operation",
+		Message:     "precondition failed",
+	},
+	{
+		Code:        ResourceExhausted,
+		Numeric:     4291,
+		HTTPStatus:  http.StatusTooManyRequests,
+		GRPCCode:    grpcResourceExhausted,
+		Severity:    SeverityWarning,
+		Retryable:   true,
+		Categories:  CategoryClient | CategoryTransient,
+		Description: "Quota or capacity exhausted",
+		Message:     "resource exhausted",
+	},
+	{
+		Code:        Gone,
+		Numeric:     4100,
+		HTTPStatus:  http.StatusGone,
+		GRPCCode:    grpcNotFound,
+		Severity:    SeverityInfo,
+		Categories:  CategoryClient,
+		Description: "Resource permanently removed",
+		Message:     "resource gone",
+	},
+	{
+		Code:        PayloadTooLarge,
+		Numeric:     4130,
+		HTTPStatus:  http.StatusRequestEntityTooLarge,
+		GRPCCode:    grpcInvalidArgument,
+		Severity:    SeverityWarning,
+		Categories:  CategoryClient,
+		Description: "Request body too large",
+		Message:     "payload too large",
+	},
+	{
+		Code:        UnsupportedMediaType,
+		Numeric:     4150,
+		HTTPStatus:  http.StatusUnsupportedMediaType,
+		GRPCCode:    grpcInvalidArgument,
+		Severity:    SeverityWarning,
+		Categories:  CategoryClient,
+		Description: "Request content type not supported",
+		Message:     "unsupported media type",
+	},
+}
+
+var registry = newCodeRegistry()
+
+type codeRegistry struct {
+	mu    sync.RWMutex
+	codes map[Code]CodeInfo
+	order []Code
+}
+
+func newCodeRegistry() *codeRegistry {
+	r := &codeRegistry{codes: make(map[Code]CodeInfo)}
+	for _, info := range standardCodes {
+		r.codes[info.Code] = info
+		r.order = append(r.order, info.Code)
+	}
+	return r
+}
+
+// RegisterCode registers a code, or updates an already registered one, so
+// that HTTP, gRPC, severity and retry helpers treat it consistently
+func RegisterCode(code Code, opts ...CodeOption) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	info, exists := registry.codes[code]
+	if !exists {
+		info = CodeInfo{Code: code}
+		registry.order = append(registry.order, code)
+	}
+	for _, opt := range opts {
+		opt(&info)
+	}
+	registry.codes[code] = info
+}
+
+// LookupCode returns the registered description of a code
+func LookupCode(code Code) (CodeInfo, bool) {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	info, ok := registry.codes[code]
+	return info, ok
+}
+
+// RegisteredCodes returns every registered code in registration order,
+// starting with the standard codes
+func RegisteredCodes() []CodeInfo {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	infos := make([]CodeInfo, 0, len(registry.order))
+	for _, code := range registry.order {
+		infos = append(infos, registry.codes[code])
+	}
+	return infos
+}
+
+// findCode returns the first registered code matching the predicate
+func findCode(match func(CodeInfo) bool) (Code, bool) {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	for _, code := range registry.order {
+		if match(registry.codes[code]) {
+			return code, true
+		}
+	}
+	return "", false
+}
+
+// CodeGRPC returns the gRPC status code registered for the given code
+// Returns 13 (Internal) if the code has no known mapping
+func CodeGRPC(code Code) uint32 {
+	if info, ok := LookupCode(code); ok && info.GRPCCode != 0 {
+		return info.GRPCCode
+	}
+	return grpcInternal
+}
+
+// CodeFromGRPC returns the first registered code using the given gRPC status code
+func CodeFromGRPC(grpcCode uint32) (Code, bool) {
+	return findCode(func(info CodeInfo) bool {
+		return info.GRPCCode == grpcCode
+	})
+}
+
+// DefaultMessage returns the default message registered for the given code
+// Returns an empty string if the code has none
+func DefaultMessage(code Code) string {
+	info, _ := LookupCode(code)
+	return info.Message
+}
@@ -0,0 +1,97 @@
+package errx
+
+import (
+	"errors"
+	"sync/atomic"
+)
+
+// Reporter ships errors to an error tracker or log as they are created
+// Implementations are called synchronously on the creating goroutine, so
+// slow backends should queue events rather than send them inline
+type Reporter interface {
+	Report(e *Error)
+}
+
+// ReporterFunc adapts a function to the Reporter interface
+type ReporterFunc func(e *Error)
+
+// Report calls f(e)
+func (f ReporterFunc) Report(e *Error) {
+	f(e)
+}
+
+// reporterBox wraps the installed reporter, since atomic values can't hold
+// interfaces of varying dynamic types
+type reporterBox struct {
+	r Reporter
+}
+
+var (
+	reporter       atomic.Pointer[reporterBox]
+	reportSeverity atomic.Int32
+)
+
+// SetReporter installs the reporter receiving every Error created by Build
+// or Wrap whose severity is at least the report severity, SeverityError by
+// default; passing nil removes it
+// An error wrapping an Error that already qualified isn't reported again, so
+// a failure is reported once where it originated rather than once per layer
+// Qualifying errors can be sampled, coalesced and rate limited with
+// WithReportSampleRate, WithReportDedup and WithReportRateLimit, applied in
+// that order
+func SetReporter(r Reporter) {
+	if r == nil {
+		reporter.Store(nil)
+		return
+	}
+	reporter.Store(&reporterBox{r: r})
+}
+
+// WithReporter installs the reporter, as SetReporter does
+func WithReporter(r Reporter) ConfigOption {
+	return func() {
+		SetReporter(r)
+	}
+}
+
+// WithReportSeverity sets the minimum severity of reported errors; 0
+// restores the default of SeverityError
+func WithReportSeverity(s Severity) ConfigOption {
+	return func() {
+		reportSeverity.Store(int32(s))
+	}
+}
+
+// currentReportSeverity returns the minimum severity of reported errors
+func currentReportSeverity() Severity {
+	if s := reportSeverity.Load(); s != 0 {
+		return Severity(s)
+	}
+	return SeverityError
+}
+
+// report passes a newly created error to the reporter if it qualifies
+func report(e *Error) {
+	box := reporter.Load()
+	if box == nil {
+		return
+	}
+
+	threshold := currentReportSeverity()
+	if GetSeverity(e) < threshold {
+		return
+	}
+	for cause := e.Err; cause != nil; cause = errors.Unwrap(cause) {
+		if inner, ok := cause.(*Error); ok && GetSeverity(inner) >= threshold {
+			return
+		}
+	}
+	if !reportSampling.sampled(e) {
+		return
+	}
+	key := Fingerprint(e)
+	if !reportDedup.admit(key, e) || !reportSampling.allowed(key) {
+		return
+	}
+	box.r.Report(e)
+}
@@ -0,0 +1,163 @@
+package errx
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Field keys of errors built from failed HTTP responses
+const (
+	HTTPStatusField   = "http_status"   // Status of the response
+	UpstreamCodeField = "upstream_code" // Code of the body that isn't a registered errx code
+)
+
+const (
+	maxResponseBody    = 64 << 10 // Amount of a response body read by FromHTTPResponse
+	maxResponseMessage = 512      // Longest plain text body used as a message
+)
+
+// responseBody holds the members of the JSON error bodies FromHTTPResponse
+// understands: errx errors, wire envelopes, Problem Details and the common
+// {"error": "..."} shape
+type responseBody struct {
+	Code        any            `json:"code"` // A string or, for many APIs, a number
+	Message     string         `json:"message"`
+	Fields      map[string]any `json:"fields"`
+	FieldErrors []FieldError   `json:"field_errors"`
+	Title       string         `json:"title"`
+	Detail      string         `json:"detail"`
+	Error       any            `json:"error"`
+}
+
+// FromHTTPResponse builds an Error describing a failed HTTP response
+// The code is taken from an errx or Problem Details body or the X-Errx-Code
+// header when present and mapped from the status otherwise; a body or header
+// code that isn't registered is kept in the upstream_code field instead, so
+// foreign codes don't replace the mapped one; the message comes
+// from the body on a best effort basis, falling back to the status text
+// An X-Errx-Id header sets the error's ID
+// The status is recorded in the http_status field and a Retry-After header as
+// the retry delay; the body is read and replaced so it can still be consumed
+// The error is Unreported, as the upstream service reported it
+// Returns nil for a nil response or a status below 400
+func FromHTTPResponse(resp *http.Response) *Error {
+	if resp == nil || resp.StatusCode < http.StatusBadRequest {
+		return nil
+	}
+
+	b := New(CodeFromHTTPStatus(resp.StatusCode)).
+		Unreported().
+		WithID(resp.Header.Get(IDHeader)).
+		WithField(HTTPStatusField, resp.StatusCode)
+	if code := resp.Header.Get(CodeHeader); code != "" {
+		upstream(b, code)
+	}
+	if d, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+		b.WithRetryAfter(d)
+	}
+
+	if resp.Body != nil {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody))
+		resp.Body.Close()
+		resp.Body = io.NopCloser(bytes.NewReader(data))
+		parseResponseBody(b, resp.Header.Get("Content-Type"), data)
+	}
+	if b.message == "" {
+		b.WithMessage(http.StatusText(resp.StatusCode))
+	}
+	return b.build(1)
+}
+
+// parseResponseBody fills in the builder from whatever the body describes
+func parseResponseBody(b *Builder, contentType string, data []byte) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if mediaType == "application/json" || strings.HasSuffix(mediaType, "+json") || data[0] == '{' {
+		var body responseBody
+		if json.Unmarshal(data, &body) == nil {
+			if code := upstreamCode(body.Code); code != "" {
+				upstream(b, code)
+			}
+			switch msg := errorMessage(body.Error); {
+			case body.Message != "":
+				b.WithMessage(body.Message)
+			case body.Detail != "":
+				b.WithMessage(body.Detail)
+			case msg != "":
+				b.WithMessage(msg)
+			case body.Title != "":
+				b.WithMessage(body.Title)
+			}
+			b.WithFields(body.Fields).WithFieldErrors(body.FieldErrors...)
+			return
+		}
+	}
+
+	if strings.HasPrefix(mediaType, "text/plain") || mediaType == "" {
+		if len(data) <= maxResponseMessage && utf8.Valid(data) {
+			b.WithMessage(string(data))
+		}
+	}
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds or as an HTTP date
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(value); err == nil {
+		return time.Duration(secs) * time.Second, secs > 0
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d, true
+		}
+	}
+	return 0, false
+}
+
+// upstream sets the code given by a response on the builder if it's
+// registered, and records it in the upstream_code field otherwise
+func upstream(b *Builder, code string) {
+	if _, ok := LookupCode(Code(code)); ok {
+		b.WithCode(Code(code))
+		return
+	}
+	b.WithField(UpstreamCodeField, code)
+}
+
+// upstreamCode returns the "code" member of a body given either as a string
+// or as a number
+func upstreamCode(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return ""
+}
+
+// errorMessage extracts the message of an "error" member given either as a
+// string or as an object with a message
+func errorMessage(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case map[string]any:
+		msg, _ := v["message"].(string)
+		return msg
+	}
+	return ""
+}
@@ -0,0 +1,134 @@
+package errx
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestFromHTTPResponse(t *testing.T) {
+	tests := []struct {
+		name         string
+		status       int
+		header       http.Header
+		body         string
+		wantCode     Code
+		wantMessage  string
+		wantUpstream any
+	}{
+		{
+			name:        "errx body",
+			status:      http.StatusBadRequest,
+			header:      http.Header{"Content-Type": {"application/json"}},
+			body:        `{"code":"VALIDATION","message":"email is invalid"}`,
+			wantCode:    Validation,
+			wantMessage: "email is invalid",
+		},
+		{
+			name:         "unregistered code",
+			status:       http.StatusNotFound,
+			header:       http.Header{"Content-Type": {"application/json"}},
+			body:         `{"code":"resource_missing","message":"no such customer"}`,
+			wantCode:     NotFound,
+			wantMessage:  "no such customer",
+			wantUpstream: "resource_missing",
+		},
+		{
+			name:         "numeric code",
+			status:       http.StatusNotFound,
+			header:       http.Header{"Content-Type": {"application/json"}},
+			body:         `{"code":404,"message":"no such customer"}`,
+			wantCode:     NotFound,
+			wantMessage:  "no such customer",
+			wantUpstream: "404",
+		},
+		{
+			name:        "problem details",
+			status:      http.StatusConflict,
+			header:      http.Header{"Content-Type": {ProblemContentType}},
+			body:        `{"type":"about:blank","title":"Conflict","status":409,"detail":"version mismatch"}`,
+			wantCode:    Conflict,
+			wantMessage: "version mismatch",
+		},
+		{
+			name:        "error member",
+			status:      http.StatusUnauthorized,
+			header:      http.Header{"Content-Type": {"application/json"}},
+			body:        `{"error":{"message":"token expired"}}`,
+			wantCode:    Unauthorized,
+			wantMessage: "token expired",
+		},
+		{
+			name:        "code header",
+			status:      http.StatusBadRequest,
+			header:      http.Header{CodeHeader: {string(Validation)}},
+			wantCode:    Validation,
+			wantMessage: http.StatusText(http.StatusBadRequest),
+		},
+		{
+			name:         "unregistered code header",
+			status:       http.StatusNotFound,
+			header:       http.Header{CodeHeader: {"TOTALLY_MADE_UP"}},
+			wantCode:     NotFound,
+			wantMessage:  http.StatusText(http.StatusNotFound),
+			wantUpstream: "TOTALLY_MADE_UP",
+		},
+		{
+			name:        "plain text",
+			status:      http.StatusServiceUnavailable,
+			header:      http.Header{"Content-Type": {"text/plain; charset=utf-8"}},
+			body:        "upstream is down",
+			wantCode:    Unavailable,
+			wantMessage: "upstream is down",
+		},
+		{
+			name:        "malformed json",
+			status:      http.StatusBadGateway,
+			header:      http.Header{"Content-Type": {"application/json"}},
+			body:        `{"code":`,
+			wantCode:    CodeFromHTTPStatus(http.StatusBadGateway),
+			wantMessage: http.StatusText(http.StatusBadGateway),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{
+				StatusCode: tt.status,
+				Header:     tt.header,
+				Body:       io.NopCloser(strings.NewReader(tt.body)),
+			}
+			e := FromHTTPResponse(resp)
+			if e == nil {
+				t.Fatal("FromHTTPResponse returned nil")
+			}
+			if e.Code != tt.wantCode {
+				t.Errorf("code = %s, want %s", e.Code, tt.wantCode)
+			}
+			if e.Message != tt.wantMessage {
+				t.Errorf("message = %q, want %q", e.Message, tt.wantMessage)
+			}
+			if got := e.Fields[UpstreamCodeField]; got != tt.wantUpstream {
+				t.Errorf("%s = %v, want %v", UpstreamCodeField, got, tt.wantUpstream)
+			}
+			if got := e.Fields[HTTPStatusField]; got != tt.status {
+				t.Errorf("%s = %v, want %d", HTTPStatusField, got, tt.status)
+			}
+
+			body, _ := io.ReadAll(resp.Body)
+			if string(body) != tt.body {
+				t.Errorf("body = %q after reading, want %q", body, tt.body)
+			}
+		})
+	}
+}
+
+func TestFromHTTPResponseSuccess(t *testing.T) {
+	if e := FromHTTPResponse(&http.Response{StatusCode: http.StatusOK}); e != nil {
+		t.Errorf("FromHTTPResponse(200) = %v, want nil", e)
+	}
+	if e := FromHTTPResponse(nil); e != nil {
+		t.Errorf("FromHTTPResponse(nil) = %v, want nil", e)
+	}
+}
@@ -0,0 +1,79 @@
+package errx
+
+// Result holds either a value or a coded error, so a pipeline can carry
+// the outcome of each step through its transformations:
+//
+//	r := errx.Map(loadUser(id), func(u User) string { return u.Name })
+//	name := r.OrElse("anonymous")
+//
+// The zero Result holds the zero value of T and no error
+type Result[T any] struct {
+	value T
+	err   error
+}
+
+// Ok returns a Result holding v
+func Ok[T any](v T) Result[T] {
+	return Result[T]{value: v}
+}
+
+// Err returns a Result holding a new Error with the given code and message
+func Err[T any](code Code, msg string) Result[T] {
+	return Result[T]{err: New(code).WithMessage(msg).build(1)}
+}
+
+// ResultOf returns a Result holding err if it's not nil and v otherwise, for
+// adapting functions returning (T, error)
+func ResultOf[T any](v T, err error) Result[T] {
+	if err != nil {
+		return Result[T]{err: err}
+	}
+	return Result[T]{value: v}
+}
+
+// Unwrap returns the value and error held by the Result
+func (r Result[T]) Unwrap() (T, error) {
+	return r.value, r.err
+}
+
+// IsOk reports whether the Result holds a value rather than an error
+func (r Result[T]) IsOk() bool {
+	return r.err == nil
+}
+
+// Err returns the error held by the Result, or nil if it holds a value
+func (r Result[T]) Err() error {
+	return r.err
+}
+
+// Code returns the code of the error held by the Result, as GetCode reports
+// it, or an empty code if it holds a value
+func (r Result[T]) Code() Code {
+	return GetCode(r.err)
+}
+
+// OrElse returns the value held by the Result, or fallback if it holds an
+// error
+func (r Result[T]) OrElse(fallback T) T {
+	if r.err != nil {
+		return fallback
+	}
+	return r.value
+}
+
+// Map applies fn to the value held by r, passing an error through unchanged
+func Map[T, U any](r Result[T], fn func(T) U) Result[U] {
+	if r.err != nil {
+		return Result[U]{err: r.err}
+	}
+	return Result[U]{value: fn(r.value)}
+}
+
+// AndThen applies a fallible step to the value held by r, passing an error
+// through unchanged
+func AndThen[T, U any](r Result[T], fn func(T) Result[U]) Result[U] {
+	if r.err != nil {
+		return Result[U]{err: r.err}
+	}
+	return fn(r.value)
+}
@@ -0,0 +1,129 @@
+// Package retry retries operations using errx codes and retryable flags
+package retry
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/nordew/go-errx"
+)
+
+// BackoffFunc returns the delay before the given retry, starting at 1
+type BackoffFunc func(retry int) time.Duration
+
+// Constant returns a BackoffFunc that always waits d
+func Constant(d time.Duration) BackoffFunc {
+	return func(int) time.Duration {
+		return d
+	}
+}
+
+// Exponential returns a BackoffFunc that doubles the delay after each retry,
+// starting at initial and never exceeding max
+func Exponential(initial, max time.Duration) BackoffFunc {
+	return func(retry int) time.Duration {
+		d := initial
+		for i := 1; i < retry && d < max; i++ {
+			d *= 2
+		}
+		return min(d, max)
+	}
+}
+
+// Option configures Do
+type Option func(*policy)
+
+type policy struct {
+	attempts  int
+	backoff   BackoffFunc
+	retryable func(error) bool
+}
+
+// Attempts sets the maximum number of attempts, 3 by default
+func Attempts(n int) Option {
+	return func(p *policy) {
+		p.attempts = max(n, 1)
+	}
+}
+
+// Backoff sets the delay between attempts, exponential from 100ms to 5s by default
+func Backoff(b BackoffFunc) Option {
+	return func(p *policy) {
+		p.backoff = b
+	}
+}
+
+// OnCodes retries only errors with one of the given codes
+// Errors explicitly marked WithRetryable(false), such as the final failure of
+// a nested Do, are never retried
+// By default errors are retried when errx.IsRetryable reports true
+func OnCodes(codes ...errx.Code) Option {
+	return func(p *policy) {
+		p.retryable = func(err error) bool {
+			if retryable, ok := errx.RetryableFlag(err); ok && !retryable {
+				return false
+			}
+			return slices.Contains(codes, errx.GetCode(err))
+		}
+	}
+}
+
+// If retries errors for which fn returns true
+func If(fn func(error) bool) Option {
+	return func(p *policy) {
+		p.retryable = fn
+	}
+}
+
+// Do calls fn until it succeeds, returns an error that shouldn't be retried,
+// runs out of attempts or ctx is done
+// The wait between attempts is the larger of the backoff and the error's
+// errx.RetryAfter hint. The final failure keeps the code of the last error
+// and records the number of attempts in the "attempts" field
+func Do(ctx context.Context, fn func(ctx context.Context) error, opts ...Option) error {
+	p := &policy{
+		attempts:  3,
+		backoff:   Exponential(100*time.Millisecond, 5*time.Second),
+		retryable: errx.IsRetryable,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = fn(ctx); err == nil {
+			return nil
+		}
+		if !p.retryable(err) {
+			return err
+		}
+		if attempt == p.attempts {
+			return exhausted(err, attempt, "operation failed after %d attempts")
+		}
+
+		delay := p.backoff(attempt)
+		if hint, ok := errx.RetryAfter(err); ok && hint > delay {
+			delay = hint
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return exhausted(err, attempt, "operation aborted after %d attempts")
+		case <-timer.C:
+		}
+	}
+}
+
+// exhausted wraps the last error with the attempt metadata
+func exhausted(err error, attempts int, format string) error {
+	return errx.New(errx.GetCode(err)).
+		WithMessagef(format, attempts).
+		WithCause(err).
+		WithField("attempts", attempts).
+		WithRetryable(false).
+		Build()
+}
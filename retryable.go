@@ -0,0 +1,101 @@
+package errx
+
+import (
+	"context"
+	"errors"
+	"net"
+	"time"
+)
+
+// CodeRetryable reports whether errors with the given code are retryable by default
+func CodeRetryable(code Code) bool {
+	info, _ := LookupCode(code)
+	return info.Retryable
+}
+
+// IsRetryable reports whether the operation that produced err may be retried
+// An explicit WithRetryable flag anywhere in the chain takes precedence,
+// followed by wrapped context errors (DeadlineExceeded is retryable, Canceled
+// is not) and net.Error timeouts; otherwise the code default applies
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	if retryable, ok := RetryableFlag(err); ok {
+		return retryable
+	}
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		return false
+	case errors.Is(err, context.DeadlineExceeded):
+		return true
+	}
+
+	if netTimeout(err) {
+		return true
+	}
+
+	var e *Error
+	if errors.As(err, &e) {
+		return CodeRetryable(e.Code)
+	}
+	return false
+}
+
+// RetryableFlag returns the flag set with WithRetryable on the outermost Error
+// of the chain having one
+// Returns false for ok if no Error in the chain sets it explicitly
+func RetryableFlag(err error) (retryable, ok bool) {
+	for cause := err; cause != nil; cause = errors.Unwrap(cause) {
+		if e, isErrx := cause.(*Error); isErrx && e.retryable != nil {
+			return *e.retryable, true
+		}
+	}
+	return false, false
+}
+
+// Timeout reports whether the error is a timeout: its code is Timeout or it
+// wraps context.DeadlineExceeded or a net.Error timeout
+// With Temporary, it makes Error satisfy net.Error, so code type-asserting
+// to it, such as HTTP clients and retry libraries, understands errx errors
+func (e *Error) Timeout() bool {
+	return e.Code == Timeout || errors.Is(e.Err, context.DeadlineExceeded) || netTimeout(e.Err)
+}
+
+// Temporary reports whether the error is retryable, as IsRetryable does
+func (e *Error) Temporary() bool {
+	return IsRetryable(e)
+}
+
+// netTimeout reports whether the chain has a net.Error timeout other than an
+// Error, whose Timeout is derived from the chain itself
+func netTimeout(err error) bool {
+	for cause := range Chain(err) {
+		if _, ok := cause.(*Error); ok {
+			continue
+		}
+		if netErr, ok := cause.(net.Error); ok && netErr.Timeout() {
+			return true
+		}
+	}
+	return false
+}
+
+// RetryAfter returns the retry delay suggested by the first error in the chain
+// that has one set with WithRetryAfter
+// Without one, the time left until the reset of a quota recorded with
+// WithRateLimit is returned if it's in the future
+func RetryAfter(err error) (time.Duration, bool) {
+	for cause := err; cause != nil; cause = errors.Unwrap(cause) {
+		if e, ok := cause.(*Error); ok && e.retryAfter > 0 {
+			return e.retryAfter, true
+		}
+	}
+	if _, _, reset, ok := RateLimit(err); ok && !reset.IsZero() {
+		if d := reset.Sub(currentTime()); d > 0 {
+			return d, true
+		}
+	}
+	return 0, false
+}
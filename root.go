@@ -0,0 +1,42 @@
+package errx
+
+import "errors"
+
+// RootCause returns the innermost error in the chain that isn't an Error or
+// RemoteError, such as the original sql.ErrNoRows or syscall error wrapped by business
+// level errors
+// Joined errors aren't descended into, so a joined cause is its own root
+// Returns nil if every layer is an errx error or err is nil
+func RootCause(err error) error {
+	var root error
+	for cause := err; cause != nil; cause = errors.Unwrap(cause) {
+		switch cause.(type) {
+		case *Error, *RemoteError:
+		default:
+			root = cause
+		}
+	}
+	return root
+}
+
+// RootMessage returns the text of the root cause, for logging the true
+// origin of a failure next to its business level message
+// If every layer is an errx error, the message of the innermost one is
+// returned
+// Returns an empty string for a nil error
+func RootMessage(err error) string {
+	if root := RootCause(err); root != nil {
+		return root.Error()
+	}
+
+	var message string
+	for cause := err; cause != nil; cause = errors.Unwrap(cause) {
+		switch e := cause.(type) {
+		case *Error:
+			message = e.Message
+		case *RemoteError:
+			message = e.Message
+		}
+	}
+	return message
+}
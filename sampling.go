@@ -0,0 +1,129 @@
+package errx
+
+import (
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+// maxRateWindows bounds the fingerprints tracked by the report rate limit;
+// expired windows are dropped once it's reached
+const maxRateWindows = 10000
+
+// sampling holds the controls deciding which qualifying errors are reported
+type sampling struct {
+	mu            sync.Mutex
+	limit         int           // Reports allowed per fingerprint and period, 0 for no limit
+	period        time.Duration // Length of a rate limit window
+	windows       map[string]*rateWindow
+	codeRates     map[Code]float64
+	severityRates map[Severity]float64
+}
+
+// rateWindow counts the reports of one fingerprint in the current window
+type rateWindow struct {
+	start time.Time
+	count int
+}
+
+var reportSampling = &sampling{}
+
+// WithReportRateLimit reports at most limit errors with the same fingerprint
+// per period, e.g. 10 per minute, so a failure repeated during an incident
+// doesn't flood the error tracker; a limit below 1 removes it
+func WithReportRateLimit(limit int, period time.Duration) ConfigOption {
+	return func() {
+		s := reportSampling
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if limit < 1 || period <= 0 {
+			s.limit, s.period = 0, 0
+		} else {
+			s.limit, s.period = limit, period
+		}
+		s.windows = nil
+	}
+}
+
+// WithReportSampleRate reports only the given fraction of errors with code,
+// between 0 (none) and 1 (all), for high-volume codes
+// A code rate takes precedence over the rate of the error's severity;
+// a negative rate removes it
+func WithReportSampleRate(code Code, rate float64) ConfigOption {
+	return func() {
+		s := reportSampling
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.codeRates = setRate(s.codeRates, code, rate)
+	}
+}
+
+// WithReportSeveritySampleRate reports only the given fraction of errors with
+// the severity, between 0 (none) and 1 (all), unless their code has its own
+// rate; a negative rate removes it
+func WithReportSeveritySampleRate(severity Severity, rate float64) ConfigOption {
+	return func() {
+		s := reportSampling
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.severityRates = setRate(s.severityRates, severity, rate)
+	}
+}
+
+// setRate stores rate for key in rates, clamped to 1, or deletes it if negative
+func setRate[K comparable](rates map[K]float64, key K, rate float64) map[K]float64 {
+	if rate < 0 {
+		delete(rates, key)
+		return rates
+	}
+	if rates == nil {
+		rates = make(map[K]float64)
+	}
+	rates[key] = min(rate, 1)
+	return rates
+}
+
+// sampled reports whether a qualifying error is kept by the sample rate of
+// its code or severity
+func (s *sampling) sampled(e *Error) bool {
+	s.mu.Lock()
+	rate, ok := s.codeRates[e.Code]
+	if !ok {
+		rate, ok = s.severityRates[GetSeverity(e)]
+	}
+	s.mu.Unlock()
+	return !ok || rate >= 1 || rand.Float64() < rate
+}
+
+// allowed reports whether an error with the fingerprint is within the rate
+// limit, counting it if so
+func (s *sampling) allowed(key string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.limit == 0 {
+		return true
+	}
+
+	t := currentTime()
+	if len(s.windows) >= maxRateWindows {
+		for k, w := range s.windows {
+			if t.Sub(w.start) >= s.period {
+				delete(s.windows, k)
+			}
+		}
+	}
+	if s.windows == nil {
+		s.windows = make(map[string]*rateWindow)
+	}
+
+	w, ok := s.windows[key]
+	if !ok || t.Sub(w.start) >= s.period {
+		s.windows[key] = &rateWindow{start: t, count: 1}
+		return true
+	}
+	if w.count >= s.limit {
+		return false
+	}
+	w.count++
+	return true
+}
@@ -0,0 +1,145 @@
+package errx
+
+import (
+	"maps"
+	"regexp"
+	"strings"
+	"sync/atomic"
+)
+
+var sanitizer atomic.Pointer[func(string) string]
+
+// SetSanitizer installs a function applied to messages and string field values
+// before errors are serialized or reported
+// Passing nil removes the sanitizer
+func SetSanitizer(fn func(string) string) {
+	if fn == nil {
+		sanitizer.Store(nil)
+		return
+	}
+	sanitizer.Store(&fn)
+}
+
+// Sanitize applies the installed sanitizer to s
+// Returns s unchanged if no sanitizer is installed
+func Sanitize(s string) string {
+	if fn := sanitizer.Load(); fn != nil {
+		return (*fn)(s)
+	}
+	return s
+}
+
+// SanitizeFields returns a copy of fields with the installed sanitizer applied
+// to every string value
+// Returns fields unchanged if no sanitizer is installed
+func SanitizeFields(fields map[string]any) map[string]any {
+	if sanitizer.Load() == nil || fields == nil {
+		return fields
+	}
+
+	sanitized := make(map[string]any, len(fields))
+	for key, value := range fields {
+		if s, ok := value.(string); ok {
+			value = Sanitize(s)
+		}
+		sanitized[key] = value
+	}
+	return sanitized
+}
+
+// PublicFields returns fields as they may be sent to clients: the installed
+// sanitizer is applied and internal fields, such as the PanicField holding a
+// recovered panic value, are dropped
+func PublicFields(fields map[string]any) map[string]any {
+	if _, ok := fields[PanicField]; !ok {
+		return SanitizeFields(fields)
+	}
+	public := maps.Clone(SanitizeFields(fields))
+	delete(public, PanicField)
+	return public
+}
+
+// SanitizeFieldErrors returns a copy of errs with the installed sanitizer
+// applied to every message and string value
+// Returns errs unchanged if no sanitizer is installed
+func SanitizeFieldErrors(errs []FieldError) []FieldError {
+	if sanitizer.Load() == nil || errs == nil {
+		return errs
+	}
+
+	sanitized := make([]FieldError, len(errs))
+	for i, fe := range errs {
+		fe.Message = Sanitize(fe.Message)
+		if s, ok := fe.Value.(string); ok {
+			fe.Value = Sanitize(s)
+		}
+		sanitized[i] = fe
+	}
+	return sanitized
+}
+
+// Scrubbers combines several sanitizers into one that applies them in order
+func Scrubbers(fns ...func(string) string) func(string) string {
+	return func(s string) string {
+		for _, fn := range fns {
+			s = fn(s)
+		}
+		return s
+	}
+}
+
+var (
+	emailPattern      = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+	bearerPattern     = regexp.MustCompile(`(?i)\b(bearer|basic)\s+[A-Za-z0-9\-._~+/]+=*`)
+	jwtPattern        = regexp.MustCompile(`\beyJ[A-Za-z0-9_\-]+\.[A-Za-z0-9_\-]+\.[A-Za-z0-9_\-]+`)
+	secretPattern     = regexp.MustCompile(`(?i)\b(api[_\-]?key|access[_\-]?token|token|secret|password|passwd)(\s*[:=]\s*)[^\s&,;]+`)
+	cardNumberPattern = regexp.MustCompile(`\b\d(?:[ \-]?\d){12,18}\b`)
+)
+
+// ScrubEmails replaces email addresses with "[email]"
+func ScrubEmails(s string) string {
+	return emailPattern.ReplaceAllString(s, "[email]")
+}
+
+// ScrubTokens replaces bearer and basic credentials, JWTs and values of
+// secret-looking keys such as "password=..." or "api_key: ..." with "[token]"
+func ScrubTokens(s string) string {
+	s = bearerPattern.ReplaceAllString(s, "$1 [token]")
+	s = jwtPattern.ReplaceAllString(s, "[token]")
+	return secretPattern.ReplaceAllString(s, "$1$2[token]")
+}
+
+// ScrubCreditCards replaces digit sequences that pass the Luhn check and look
+// like payment card numbers with "[card]"
+func ScrubCreditCards(s string) string {
+	return cardNumberPattern.ReplaceAllStringFunc(s, func(match string) string {
+		digits := strings.NewReplacer(" ", "", "-", "").Replace(match)
+		if len(digits) < 13 || !luhn(digits) {
+			return match
+		}
+		return "[card]"
+	})
+}
+
+// ScrubAll applies every built-in scrubber
+func ScrubAll(s string) string {
+	return Scrubbers(ScrubTokens, ScrubEmails, ScrubCreditCards)(s)
+}
+
+// luhn reports whether a string of digits passes the Luhn checksum
+func luhn(digits string) bool {
+	sum := 0
+	double := false
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
@@ -0,0 +1,103 @@
+// Package sentryx reports errx errors to Sentry
+package sentryx
+
+import (
+	"errors"
+	"reflect"
+	"slices"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/nordew/go-errx"
+)
+
+// CodeTag is the Sentry tag holding the errx code
+const CodeTag = "errx.code"
+
+// Level returns the Sentry level matching an error's severity
+func Level(err error) sentry.Level {
+	switch errx.GetSeverity(err) {
+	case errx.SeverityInfo:
+		return sentry.LevelInfo
+	case errx.SeverityWarning:
+		return sentry.LevelWarning
+	case errx.SeverityCritical:
+		return sentry.LevelFatal
+	}
+	return sentry.LevelError
+}
+
+// NewEvent converts an error into a Sentry event
+// The code becomes a tag, the fields become the "fields" context, and every
+// layer of the cause chain becomes an exception entry with its stack frames
+// if they were captured; the installed sanitizer is applied to all text
+func NewEvent(err error) *sentry.Event {
+	event := sentry.NewEvent()
+	event.Level = Level(err)
+	event.Message = errx.Sanitize(err.Error())
+	event.Tags[CodeTag] = string(errx.GetCode(err))
+
+	if fields := errx.SanitizeFields(errx.GetFields(err)); len(fields) > 0 {
+		event.Contexts["fields"] = sentry.Context(fields)
+	}
+
+	for cause := err; cause != nil; cause = errors.Unwrap(cause) {
+		event.Exception = append(event.Exception, exception(cause))
+	}
+	// Sentry expects the innermost exception first
+	slices.Reverse(event.Exception)
+	return event
+}
+
+// exception converts one layer of an error chain into a Sentry exception
+func exception(err error) sentry.Exception {
+	e, ok := err.(*errx.Error)
+	if !ok {
+		return sentry.Exception{
+			Type:  reflect.TypeOf(err).String(),
+			Value: errx.Sanitize(err.Error()),
+		}
+	}
+
+	exc := sentry.Exception{
+		Type:  string(e.Code),
+		Value: errx.Sanitize(e.Message),
+	}
+	if frames := e.StackTrace(); len(frames) > 0 {
+		stacktrace := &sentry.Stacktrace{Frames: make([]sentry.Frame, 0, len(frames))}
+		for _, frame := range frames {
+			stacktrace.Frames = append(stacktrace.Frames, sentry.NewFrame(frame))
+		}
+		// Sentry expects the outermost caller first
+		slices.Reverse(stacktrace.Frames)
+		exc.Stacktrace = stacktrace
+	}
+	return exc
+}
+
+// Reporter sends errx errors to Sentry through a hub
+// It implements errx.Reporter, so it can be installed with errx.SetReporter
+type Reporter struct {
+	hub *sentry.Hub
+}
+
+// NewReporter creates a Reporter using the given hub, or the current hub if nil
+func NewReporter(hub *sentry.Hub) *Reporter {
+	if hub == nil {
+		hub = sentry.CurrentHub()
+	}
+	return &Reporter{hub: hub}
+}
+
+// Capture converts the error into an event and sends it to Sentry
+// Returns nil for a nil error or if the event was not sent
+func (r *Reporter) Capture(err error) *sentry.EventID {
+	if err == nil {
+		return nil
+	}
+	return r.hub.CaptureEvent(NewEvent(err))
+}
+
+// Report sends the error to Sentry, implementing errx.Reporter
+func (r *Reporter) Report(e *errx.Error) {
+	r.Capture(e)
+}
@@ -0,0 +1,56 @@
+package errx
+
+import "errors"
+
+// Severity indicates how serious an error is for logging and reporting
+type Severity int
+
+// Severity levels, ordered from least to most severe
+const (
+	SeverityInfo     Severity = iota + 1 // Expected outcome, e.g. a missing resource
+	SeverityWarning                      // Client mistake worth noticing
+	SeverityError                        // Failure that needs attention
+	SeverityCritical                     // Failure that needs immediate attention
+)
+
+// String returns the lowercase name of the severity
+func (s Severity) String() string {
+	switch s {
+	case SeverityInfo:
+		return "info"
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	case SeverityCritical:
+		return "critical"
+	}
+	return "unknown"
+}
+
+// CodeSeverity returns the default severity registered for the given code
+// Returns SeverityError if the code has no known default
+func CodeSeverity(code Code) Severity {
+	if info, ok := LookupCode(code); ok && info.Severity != 0 {
+		return info.Severity
+	}
+	return SeverityError
+}
+
+// GetSeverity returns the severity of an error
+// An explicit severity set with WithSeverity takes precedence over the code default
+// Returns SeverityError if the error isn't an Error type and 0 for a nil error
+func GetSeverity(err error) Severity {
+	if err == nil {
+		return 0
+	}
+
+	var e *Error
+	if errors.As(err, &e) {
+		if e.severity != 0 {
+			return e.severity
+		}
+		return CodeSeverity(e.Code)
+	}
+	return SeverityError
+}
@@ -0,0 +1,92 @@
+package errx
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"maps"
+	"slices"
+)
+
+// LogValue implements slog.LogValuer so the error is logged as a group of
+// structured attributes instead of a single string
+func (e *Error) LogValue() slog.Value {
+	return slog.GroupValue(e.slogAttrs()...)
+}
+
+// SlogAttrs returns the structured attributes describing an error with the
+// installed sanitizer applied
+// Errors that aren't Error values produce a single message attribute
+func SlogAttrs(err error) []slog.Attr {
+	if err == nil {
+		return nil
+	}
+
+	var e *Error
+	if !errors.As(err, &e) {
+		return []slog.Attr{slog.String("message", Sanitize(err.Error()))}
+	}
+	return e.slogAttrs()
+}
+
+func (e *Error) slogAttrs() []slog.Attr {
+	attrs := []slog.Attr{
+		slog.String("code", string(e.Code)),
+		slog.Int("numeric_code", CodeNumeric(e.Code)),
+		slog.String("message", Sanitize(e.Message)),
+	}
+	if ns := GetNamespace(e); ns != "" {
+		attrs = append(attrs, slog.String("namespace", ns))
+	}
+	if ops := Ops(e); len(ops) > 0 {
+		attrs = append(attrs, slog.Any("ops", ops))
+	}
+	if e.Err != nil {
+		attrs = append(attrs, slog.String("cause", Sanitize(e.Err.Error())))
+	}
+
+	if len(e.Fields) > 0 {
+		sanitized := SanitizeFields(e.Fields)
+		fields := make([]any, 0, len(sanitized))
+		for _, key := range slices.Sorted(maps.Keys(sanitized)) {
+			fields = append(fields, slog.Any(key, sanitized[key]))
+		}
+		attrs = append(attrs, slog.Group("fields", fields...))
+	}
+	return attrs
+}
+
+// SlogLevel returns the slog level matching an error's severity
+func SlogLevel(err error) slog.Level {
+	switch GetSeverity(err) {
+	case SeverityInfo:
+		return slog.LevelInfo
+	case SeverityWarning:
+		return slog.LevelWarn
+	case SeverityCritical:
+		return slog.LevelError + 4
+	}
+	return slog.LevelError
+}
+
+// Log logs an error at the level derived from its severity, e.g. NotFound at
+// Info and Internal at Error, with its structured attributes in an "error" group
+func Log(logger *slog.Logger, err error) {
+	LogContext(context.Background(), logger, err)
+}
+
+// LogContext is like Log but passes ctx to the logger
+// Extra attributes, such as the request method used by the framework
+// adapters, are logged next to the "error" group
+func LogContext(ctx context.Context, logger *slog.Logger, err error, attrs ...any) {
+	if err == nil {
+		return
+	}
+
+	errAttrs := SlogAttrs(err)
+	group := make([]any, 0, len(errAttrs))
+	for _, attr := range errAttrs {
+		group = append(group, attr)
+	}
+	logger.Log(ctx, SlogLevel(err), Sanitize(GetMessage(err)), append(attrs, slog.Group("error", group...))...)
+}
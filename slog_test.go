@@ -0,0 +1,56 @@
+package errx
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+)
+
+func TestLogContext(t *testing.T) {
+	SetSanitizer(ScrubTokens)
+	defer SetSanitizer(nil)
+
+	tests := []struct {
+		name    string
+		err     error
+		level   string
+		message string
+	}{
+		{"not found", New(NotFound).WithMessage("user not found").WithField("user_id", 7).Error(), "INFO", "user not found"},
+		{"internal", New(Internal).WithMessage("login failed password=hunter2").Error(), "ERROR", "login failed password=[token]"},
+		{"critical", New(Internal).WithMessage("disk full").WithSeverity(SeverityCritical).Error(), "ERROR+4", "disk full"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+			LogContext(context.Background(), logger, tt.err, slog.String("method", "GET"))
+
+			var record map[string]any
+			if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+				t.Fatalf("decoding %s: %v", buf.Bytes(), err)
+			}
+			if record["level"] != tt.level || record["msg"] != tt.message {
+				t.Errorf("record = %s, want level %s and message %q", buf.Bytes(), tt.level, tt.message)
+			}
+			if record["method"] != "GET" {
+				t.Errorf("method = %v, want GET", record["method"])
+			}
+			group, _ := record["error"].(map[string]any)
+			if group["code"] != string(GetCode(tt.err)) {
+				t.Errorf("error group = %v, want code %s", group, GetCode(tt.err))
+			}
+		})
+	}
+}
+
+func TestLogContextNil(t *testing.T) {
+	var buf bytes.Buffer
+	LogContext(context.Background(), slog.New(slog.NewJSONHandler(&buf, nil)), nil)
+	if buf.Len() != 0 {
+		t.Errorf("logged %s for a nil error", buf.Bytes())
+	}
+}
@@ -0,0 +1,64 @@
+package errx
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"sync/atomic"
+)
+
+var skipSource atomic.Bool
+
+// SetCaptureSource enables or disables recording the call site of errors
+// built with Build or Wrap; enabled by default
+func SetCaptureSource(enabled bool) {
+	skipSource.Store(!enabled)
+}
+
+// Source describes the location in the code where an error was created
+type Source struct {
+	Function string `json:"function"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+}
+
+// String formats the source as "function (file:line)" using the file's base name
+func (s Source) String() string {
+	if s.File == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s (%s:%d)", s.Function, filepath.Base(s.File), s.Line)
+}
+
+// callerPC returns the program counter of the caller, skipping the given
+// number of frames above the caller of callerPC itself
+// Returns 0 if source capture is disabled
+func callerPC(skip int) uintptr {
+	if skipSource.Load() {
+		return 0
+	}
+
+	var pcs [1]uintptr
+	if runtime.Callers(skip+2, pcs[:]) == 0 {
+		return 0
+	}
+	return pcs[0]
+}
+
+// Source returns the location where the error was built or wrapped
+// Returns the zero Source if the location wasn't recorded
+func (e *Error) Source() Source {
+	if e.source != nil {
+		return *e.source
+	}
+	if e.pc == 0 {
+		return Source{}
+	}
+
+	frame, _ := runtime.CallersFrames([]uintptr{e.pc}).Next()
+	return Source{
+		Function: frame.Function,
+		File:     frame.File,
+		Line:     frame.Line,
+	}
+}
@@ -0,0 +1,95 @@
+// Package sqlx maps database/sql errors to errx errors
+package sqlx
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"strings"
+
+	"github.com/nordew/go-errx"
+)
+
+// MapError wraps a database/sql error in an Error with a code describing it
+// sql.ErrNoRows maps to NotFound, unique violations to AlreadyExists, other
+// constraint violations to Conflict, deadlocks and timeouts to Timeout and
+// broken connections to Unavailable; anything else maps to Internal
+// Errors that already carry an errx code are returned unchanged
+// Returns nil for a nil error
+func MapError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var e *errx.Error
+	if errors.As(err, &e) {
+		return err
+	}
+	return errx.WrapSkip(1, err, Code(err), "")
+}
+
+// Code returns the errx code describing a database/sql error
+// Drivers exposing a SQLState() method are classified by SQLSTATE, others
+// by well-known error values and message text
+func Code(err error) errx.Code {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return errx.NotFound
+	case errors.Is(err, sql.ErrConnDone), errors.Is(err, driver.ErrBadConn):
+		return errx.Unavailable
+	}
+
+	var s interface{ SQLState() string }
+	if errors.As(err, &s) {
+		if code, ok := CodeFromSQLState(s.SQLState()); ok {
+			return code
+		}
+	}
+	return codeFromMessage(err.Error())
+}
+
+// CodeFromSQLState returns the errx code describing a SQLSTATE error code
+// Reports false if the state has no specific mapping
+func CodeFromSQLState(state string) (errx.Code, bool) {
+	switch state {
+	case "23505": // unique_violation
+		return errx.AlreadyExists, true
+	case "40001", "40P01": // serialization_failure, deadlock_detected
+		return errx.Timeout, true
+	case "57014": // query_canceled, includes statement timeouts
+		return errx.Timeout, true
+	case "55P03": // lock_not_available
+		return errx.Timeout, true
+	case "42501": // insufficient_privilege
+		return errx.Forbidden, true
+	}
+
+	switch {
+	case strings.HasPrefix(state, "23"): // integrity constraint violation
+		return errx.Conflict, true
+	case strings.HasPrefix(state, "22"): // data exception
+		return errx.BadRequest, true
+	case strings.HasPrefix(state, "08"): // connection exception
+		return errx.Unavailable, true
+	case strings.HasPrefix(state, "53"): // insufficient resources
+		return errx.ResourceExhausted, true
+	case strings.HasPrefix(state, "28"): // invalid authorization
+		return errx.Unauthorized, true
+	}
+	return "", false
+}
+
+// codeFromMessage classifies drivers that only report errors as text
+func codeFromMessage(msg string) errx.Code {
+	msg = strings.ToLower(msg)
+	switch {
+	case strings.Contains(msg, "duplicate"), strings.Contains(msg, "unique constraint"):
+		return errx.AlreadyExists
+	case strings.Contains(msg, "constraint"), strings.Contains(msg, "foreign key"):
+		return errx.Conflict
+	case strings.Contains(msg, "deadlock"), strings.Contains(msg, "lock wait timeout"),
+		strings.Contains(msg, "timeout"), strings.Contains(msg, "database is locked"):
+		return errx.Timeout
+	}
+	return errx.Internal
+}
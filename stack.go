@@ -0,0 +1,41 @@
+package errx
+
+import "runtime"
+
+// maxStackDepth is the default limit of frames recorded for a stack trace
+const maxStackDepth = 32
+
+// stack holds the program counters captured when an error was created
+type stack []uintptr
+
+// callers records the current call stack, skipping the given number of frames
+// above the caller of callers itself
+func callers(skip int) stack {
+	pcs := make([]uintptr, currentStackDepth())
+	n := runtime.Callers(skip+2, pcs)
+	return pcs[:n]
+}
+
+// frames resolves the recorded program counters into runtime frames
+func (s stack) frames() []runtime.Frame {
+	if len(s) == 0 {
+		return nil
+	}
+
+	frames := make([]runtime.Frame, 0, len(s))
+	iter := runtime.CallersFrames(s)
+	for {
+		frame, more := iter.Next()
+		frames = append(frames, frame)
+		if !more {
+			break
+		}
+	}
+	return frames
+}
+
+// StackTrace returns the frames recorded when the error was built
+// Returns nil if the error was created without a stack trace
+func (e *Error) StackTrace() []runtime.Frame {
+	return e.stack.frames()
+}
@@ -0,0 +1,57 @@
+package errx
+
+import (
+	"errors"
+	"strings"
+	"text/template"
+)
+
+// WithTemplate sets the message from a text/template rendered with named
+// parameters, e.g. WithTemplate("order {{.OrderID}} not found", params)
+// The raw template and parameters are kept on the error so fingerprints group
+// occurrences regardless of the values and catalogs can translate the
+// template; the message is the rendered text
+// If the template can't be parsed or executed the raw template is used as the
+// message
+func (b *Builder) WithTemplate(tmpl string, params map[string]any) *Builder {
+	if params == nil {
+		params = map[string]any{}
+	}
+	b.message = renderTemplate(tmpl, params)
+	b.template = tmpl
+	b.templateParams = params
+	return b
+}
+
+// Template returns the message template and the parameters it was rendered with
+// For messages set with WithMessagef the template is the format string and the
+// parameters are nil
+func (e *Error) Template() (string, map[string]any) {
+	return e.template, e.templateParams
+}
+
+// GetTemplate returns the outermost template set with WithTemplate in the
+// error chain and its parameters
+// Returns an empty template if none was set
+func GetTemplate(err error) (string, map[string]any) {
+	for cause := err; cause != nil; cause = errors.Unwrap(cause) {
+		if e, ok := cause.(*Error); ok && e.templateParams != nil {
+			return e.template, e.templateParams
+		}
+	}
+	return "", nil
+}
+
+// renderTemplate executes tmpl with params, returning tmpl itself on failure
+func renderTemplate(tmpl string, params map[string]any) string {
+	t, err := template.New("message").Parse(tmpl)
+	if err != nil {
+		return tmpl
+	}
+
+	var b strings.Builder
+	if err := t.Execute(&b, params); err != nil {
+		return tmpl
+	}
+	return b.String()
+}
@@ -0,0 +1,86 @@
+package errx
+
+import (
+	"context"
+	"time"
+)
+
+// Field names used by WithTimeoutInfo
+const (
+	DeadlineField = "deadline"
+	ElapsedField  = "elapsed"
+)
+
+// WithTimeoutInfo records the deadline an operation had and how long it ran,
+// so a timeout shows how badly it overran its budget
+// The values are stored as the deadline and elapsed fields and read back with
+// TimeoutInfo; a zero deadline isn't recorded
+func (b *Builder) WithTimeoutInfo(deadline time.Time, elapsed time.Duration) *Builder {
+	if !deadline.IsZero() {
+		b.WithField(DeadlineField, deadline)
+	}
+	return b.WithField(ElapsedField, elapsed)
+}
+
+// WithContextTimeout records the deadline of ctx, if it has one, and the time
+// elapsed since start, as WithTimeoutInfo does:
+//
+//	start := time.Now()
+//	if err := call(ctx); errors.Is(err, context.DeadlineExceeded) {
+//		return errx.NewTimeout().WithCause(err).WithContextTimeout(ctx, start).Build()
+//	}
+func (b *Builder) WithContextTimeout(ctx context.Context, start time.Time) *Builder {
+	deadline, _ := ctx.Deadline()
+	return b.WithTimeoutInfo(deadline, time.Since(start))
+}
+
+// TimeoutInfo returns the deadline and elapsed time recorded with
+// WithTimeoutInfo on the outermost Error in the chain having them
+// Values restored from JSON are converted back; a deadline that wasn't
+// recorded is returned as the zero time
+func TimeoutInfo(err error) (deadline time.Time, elapsed time.Duration, ok bool) {
+	for e := range ChainErrx(err) {
+		if elapsed, ok := durationField(e.Fields[ElapsedField]); ok {
+			deadline, _ := timeField(e.Fields[DeadlineField])
+			return deadline, elapsed, true
+		}
+	}
+	return time.Time{}, 0, false
+}
+
+// Deadline returns the deadline recorded with WithTimeoutInfo
+func (e *TimeoutError) Deadline() (time.Time, bool) {
+	return timeField(e.Fields[DeadlineField])
+}
+
+// Elapsed returns the duration recorded with WithTimeoutInfo
+func (e *TimeoutError) Elapsed() (time.Duration, bool) {
+	return durationField(e.Fields[ElapsedField])
+}
+
+// timeField converts a field value holding a time, possibly decoded from JSON
+func timeField(v any) (time.Time, bool) {
+	switch v := v.(type) {
+	case time.Time:
+		return v, true
+	case string:
+		t, err := time.Parse(time.RFC3339Nano, v)
+		return t, err == nil
+	}
+	return time.Time{}, false
+}
+
+// durationField converts a field value holding a duration, possibly decoded
+// from JSON as nanoseconds
+func durationField(v any) (time.Duration, bool) {
+	switch v := v.(type) {
+	case time.Duration:
+		return v, true
+	case float64:
+		return time.Duration(v), true
+	case string:
+		d, err := time.ParseDuration(v)
+		return d, err == nil
+	}
+	return 0, false
+}
@@ -0,0 +1,43 @@
+package errx
+
+import (
+	"errors"
+	"sync/atomic"
+	"time"
+)
+
+var captureTime atomic.Bool
+
+// SetCaptureTime enables or disables recording the creation time of errors
+// built with Build or Wrap; disabled by default
+func SetCaptureTime(enabled bool) {
+	captureTime.Store(enabled)
+}
+
+// now returns the creation time to record, or the zero time if disabled
+func now() time.Time {
+	if !captureTime.Load() {
+		return time.Time{}
+	}
+	return currentTime()
+}
+
+// GetTime returns the creation time of the original failure, which is the
+// innermost time recorded in the chain
+// Returns the zero time if no time was recorded
+func GetTime(err error) time.Time {
+	var t time.Time
+	for cause := err; cause != nil; cause = errors.Unwrap(cause) {
+		switch e := cause.(type) {
+		case *Error:
+			if !e.time.IsZero() {
+				t = e.time
+			}
+		case *RemoteError:
+			if !e.Time.IsZero() {
+				t = e.Time
+			}
+		}
+	}
+	return t
+}
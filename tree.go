@@ -0,0 +1,103 @@
+package errx
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ChainNode is one error in the tree formed by a cause chain, where wrapped
+// errors are children and each member of a joined error is a child of its own
+type ChainNode struct {
+	Code     Code           `json:"code,omitempty"` // Empty for errors that aren't Error values
+	Message  string         `json:"message"`        // Message of an Error, the text of other errors
+	Type     string         `json:"type,omitempty"` // Go type of errors that aren't Error values
+	Fields   map[string]any `json:"fields,omitempty"`
+	Children []*ChainNode   `json:"children,omitempty"`
+}
+
+// ToChainNode converts an error and its causes into a tree
+// Returns nil for a nil error
+func ToChainNode(err error) *ChainNode {
+	if err == nil {
+		return nil
+	}
+
+	node := &ChainNode{}
+	var causes []error
+	if e, ok := err.(*Error); ok {
+		node.Code = e.Code
+		node.Message = e.Message
+		node.Fields = e.Fields
+		causes = []error{e.Err}
+		if joined, ok := e.Err.(interface{ Unwrap() []error }); ok {
+			// A joined cause adds no information of its own, so its members
+			// become children of the wrapping error
+			causes = joined.Unwrap()
+		}
+	} else {
+		node.Message = err.Error()
+		node.Type = fmt.Sprintf("%T", err)
+		switch u := err.(type) {
+		case interface{ Unwrap() []error }:
+			causes = u.Unwrap()
+		case interface{ Unwrap() error }:
+			causes = []error{u.Unwrap()}
+		}
+	}
+
+	for _, cause := range causes {
+		if child := ToChainNode(cause); child != nil {
+			node.Children = append(node.Children, child)
+		}
+	}
+	return node
+}
+
+// Tree renders an error and its causes as an indented tree, one error per
+// line, so aggregated failures such as joined errors stay readable:
+//
+//	[UNAVAILABLE] batch failed
+//	├── [NOT_FOUND] user 1 not found
+//	└── [INTERNAL] query failed
+//	    └── connection refused (*errors.errorString)
+//
+// Returns an empty string for a nil error
+func Tree(err error) string {
+	return ToChainNode(err).String()
+}
+
+// String renders the node and its descendants as Tree does
+func (n *ChainNode) String() string {
+	if n == nil {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString(n.label())
+	n.writeChildren(&b, "")
+	return b.String()
+}
+
+// label returns the line describing the node itself
+func (n *ChainNode) label() string {
+	// Multi-line texts, such as those of joined errors, would break the tree
+	message := strings.ReplaceAll(n.Message, "\n", "; ")
+	if n.Code != "" {
+		return fmt.Sprintf("[%s] %s", n.Code, message)
+	}
+	if n.Type != "" {
+		return fmt.Sprintf("%s (%s)", message, n.Type)
+	}
+	return message
+}
+
+// writeChildren writes the subtrees of the node, each line starting with prefix
+func (n *ChainNode) writeChildren(b *strings.Builder, prefix string) {
+	for i, child := range n.Children {
+		branch, indent := "├── ", "│   "
+		if i == len(n.Children)-1 {
+			branch, indent = "└── ", "    "
+		}
+		b.WriteString("\n" + prefix + branch + child.label())
+		child.writeChildren(b, prefix+indent)
+	}
+}
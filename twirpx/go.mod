@@ -0,0 +1,12 @@
+module github.com/nordew/go-errx/twirpx
+
+go 1.24.1
+
+require github.com/nordew/go-errx v0.0.0
+
+require (
+	github.com/pkg/errors v0.9.1 // indirect
+	github.com/twitchtv/twirp v8.1.3+incompatible
+)
+
+replace github.com/nordew/go-errx => ../
@@ -0,0 +1,173 @@
+// Package twirpx converts between errx errors and Twirp errors
+package twirpx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/nordew/go-errx"
+	"github.com/twitchtv/twirp"
+)
+
+// Metadata keys used for the errx code and ID; fields use their own keys
+const (
+	CodeMeta = "errx_code"
+	IDMeta   = "errx_id"
+)
+
+// grpcCodes maps gRPC code numbers, which errx registers for every code, to
+// the Twirp codes with the same meaning
+var grpcCodes = [...]twirp.ErrorCode{
+	1:  twirp.Canceled,
+	2:  twirp.Unknown,
+	3:  twirp.InvalidArgument,
+	4:  twirp.DeadlineExceeded,
+	5:  twirp.NotFound,
+	6:  twirp.AlreadyExists,
+	7:  twirp.PermissionDenied,
+	8:  twirp.ResourceExhausted,
+	9:  twirp.FailedPrecondition,
+	10: twirp.Aborted,
+	11: twirp.OutOfRange,
+	12: twirp.Unimplemented,
+	13: twirp.Internal,
+	14: twirp.Unavailable,
+	15: twirp.DataLoss,
+	16: twirp.Unauthenticated,
+}
+
+// CodeToTwirp returns the Twirp code matching an errx code
+// Returns twirp.Internal if the code has no known mapping
+func CodeToTwirp(code errx.Code) twirp.ErrorCode {
+	if n := errx.CodeGRPC(code); int(n) < len(grpcCodes) && grpcCodes[n] != "" {
+		return grpcCodes[n]
+	}
+	return twirp.Internal
+}
+
+// CodeFromTwirp returns the errx code that best describes a Twirp code
+// Returns Internal for codes without a closer match
+func CodeFromTwirp(code twirp.ErrorCode) errx.Code {
+	switch code {
+	case twirp.InvalidArgument:
+		return errx.Validation
+	case twirp.Malformed, twirp.OutOfRange:
+		return errx.BadRequest
+	case twirp.FailedPrecondition:
+		return errx.PreconditionFailed
+	case twirp.Unauthenticated:
+		return errx.Unauthorized
+	case twirp.PermissionDenied:
+		return errx.Forbidden
+	case twirp.NotFound, twirp.BadRoute:
+		return errx.NotFound
+	case twirp.Aborted:
+		return errx.Conflict
+	case twirp.AlreadyExists:
+		return errx.AlreadyExists
+	case twirp.DeadlineExceeded:
+		return errx.Timeout
+	case twirp.Canceled:
+		return errx.Cancelled
+	case twirp.ResourceExhausted:
+		return errx.ResourceExhausted
+	case twirp.Unimplemented:
+		return errx.NotImplemented
+	case twirp.Unavailable:
+		return errx.Unavailable
+	}
+	return errx.Internal
+}
+
+// ToTwirpError converts an error into a Twirp error
+// The errx code and ID are kept in the errx_code and errx_id metadata and the
+// fields as metadata of their own, so FromTwirpError can restore them; Twirp
+// errors are returned unchanged
+// Server errors (HTTP status 5xx) only carry the code, the ID and a generic
+// message, as httpx.NewResponse reports them, so internal details such as
+// recovered panic values don't leak to clients
+// Returns nil for a nil error
+func ToTwirpError(err error) twirp.Error {
+	if err == nil {
+		return nil
+	}
+
+	var te twirp.Error
+	var e *errx.Error
+	if errors.As(err, &te) && !errors.As(err, &e) {
+		return te
+	}
+
+	code := errx.GetCode(err)
+	if status := errx.HTTPStatus(err); status >= http.StatusInternalServerError {
+		te = twirp.NewError(CodeToTwirp(code), http.StatusText(status))
+	} else {
+		te = twirp.NewError(CodeToTwirp(code), errx.Sanitize(errx.GetMessage(err)))
+		for key, value := range errx.PublicFields(errx.GetFields(err)) {
+			te = te.WithMeta(key, fmt.Sprint(value))
+		}
+	}
+	te = te.WithMeta(CodeMeta, string(code))
+	if id := errx.GetID(err); id != "" {
+		te = te.WithMeta(IDMeta, id)
+	}
+	return te
+}
+
+// FromTwirpError converts a Twirp error back into an Error, restoring the
+// errx code, ID and fields from its metadata
+// Errors that aren't Twirp errors are returned as Internal errors wrapping
+// them; Twirp errors are Unreported, as the server reported them
+// Returns nil for a nil error
+func FromTwirpError(err error) *errx.Error {
+	if err == nil {
+		return nil
+	}
+
+	var te twirp.Error
+	if !errors.As(err, &te) {
+		return errx.Wrap(err, errx.Internal, "")
+	}
+
+	code := CodeFromTwirp(te.Code())
+	if c := te.Meta(CodeMeta); c != "" {
+		code = errx.Code(c)
+	}
+	b := errx.New(code).Unreported().WithMessage(te.Msg()).WithID(te.Meta(IDMeta))
+	for key, value := range te.MetaMap() {
+		if key != CodeMeta && key != IDMeta {
+			b.WithField(key, value)
+		}
+	}
+	return b.Build()
+}
+
+// ServerInterceptor converts errors returned by Twirp handlers with ToTwirpError
+// Use it with twirp.WithServerInterceptors
+func ServerInterceptor() twirp.Interceptor {
+	return func(next twirp.Method) twirp.Method {
+		return func(ctx context.Context, req any) (any, error) {
+			resp, err := next(ctx, req)
+			if err != nil {
+				return resp, ToTwirpError(err)
+			}
+			return resp, nil
+		}
+	}
+}
+
+// ClientInterceptor converts errors received by Twirp clients with FromTwirpError
+// Use it with twirp.WithClientInterceptors
+func ClientInterceptor() twirp.Interceptor {
+	return func(next twirp.Method) twirp.Method {
+		return func(ctx context.Context, req any) (any, error) {
+			resp, err := next(ctx, req)
+			if err != nil {
+				return resp, FromTwirpError(err)
+			}
+			return resp, nil
+		}
+	}
+}
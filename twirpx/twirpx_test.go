@@ -0,0 +1,82 @@
+package twirpx
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/nordew/go-errx"
+	"github.com/twitchtv/twirp"
+)
+
+func TestToTwirpError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		code     twirp.ErrorCode
+		msg      string
+		wantMeta map[string]string
+		noMeta   []string
+	}{
+		{
+			name:     "client error",
+			err:      errx.New(errx.NotFound).WithMessage("user not found").WithID("id-1").WithField("user_id", 7).Error(),
+			code:     twirp.NotFound,
+			msg:      "user not found",
+			wantMeta: map[string]string{CodeMeta: string(errx.NotFound), IDMeta: "id-1", "user_id": "7"},
+		},
+		{
+			name:     "internal",
+			err:      errx.New(errx.Internal).WithMessage("query failed: password=hunter2").WithID("id-2").WithField("dsn", "postgres://db").Error(),
+			code:     twirp.Internal,
+			msg:      http.StatusText(http.StatusInternalServerError),
+			wantMeta: map[string]string{CodeMeta: string(errx.Internal), IDMeta: "id-2"},
+			noMeta:   []string{"dsn"},
+		},
+		{
+			name:     "panic",
+			err:      errx.FromPanic("nil map write"),
+			code:     twirp.Internal,
+			msg:      http.StatusText(http.StatusInternalServerError),
+			wantMeta: map[string]string{CodeMeta: string(errx.Internal)},
+			noMeta:   []string{errx.PanicField},
+		},
+		{
+			name:     "unavailable",
+			err:      errx.New(errx.Unavailable).WithMessage("pool exhausted").Error(),
+			code:     twirp.Unavailable,
+			msg:      http.StatusText(http.StatusServiceUnavailable),
+			wantMeta: map[string]string{CodeMeta: string(errx.Unavailable)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			te := ToTwirpError(tt.err)
+			if te.Code() != tt.code || te.Msg() != tt.msg {
+				t.Errorf("ToTwirpError = %s %q, want %s %q", te.Code(), te.Msg(), tt.code, tt.msg)
+			}
+			for key, want := range tt.wantMeta {
+				if got := te.Meta(key); got != want {
+					t.Errorf("meta %s = %q, want %q", key, got, want)
+				}
+			}
+			for _, key := range tt.noMeta {
+				if got := te.Meta(key); got != "" {
+					t.Errorf("meta %s = %q, want none", key, got)
+				}
+			}
+
+			back := FromTwirpError(te)
+			if back.Code != errx.GetCode(tt.err) {
+				t.Errorf("FromTwirpError code = %s, want %s", back.Code, errx.GetCode(tt.err))
+			}
+		})
+	}
+}
+
+func TestToTwirpErrorKeepsTwirpErrors(t *testing.T) {
+	te := twirp.NewError(twirp.Unauthenticated, "token expired")
+	if got := ToTwirpError(te); got != te {
+		t.Errorf("ToTwirpError(twirp error) = %v, want it unchanged", got)
+	}
+}
@@ -0,0 +1,490 @@
+package errx
+
+import "errors"
+
+// Typed errors let callers match codes with errors.As:
+//
+//	var nf *errx.NotFoundError
+//	if errors.As(err, &nf) {
+//		fmt.Println(nf.Resource(), nf.Message)
+//	}
+//
+// Each type shares its memory with the matched Error, so its exported fields
+// (Code, Message, Err, Fields) are available directly
+
+// As finds the first error in the chain matching T, as errors.As does,
+// without declaring a target variable first:
+//
+//	if nf, ok := errx.As[*errx.NotFoundError](err); ok {
+//		fmt.Println(nf.Resource())
+//	}
+func As[T error](err error) (T, bool) {
+	var target T
+	ok := errors.As(err, &target)
+	return target, ok
+}
+
+// As implements matching the typed errors below for the errors.As function
+func (e *Error) As(target any) bool {
+	switch t := target.(type) {
+	case **BadRequestError:
+		if e.Code == BadRequest {
+			*t = (*BadRequestError)(e)
+			return true
+		}
+	case **ValidationError:
+		if e.Code == Validation {
+			*t = (*ValidationError)(e)
+			return true
+		}
+	case **UnauthorizedError:
+		if e.Code == Unauthorized {
+			*t = (*UnauthorizedError)(e)
+			return true
+		}
+	case **ForbiddenError:
+		if e.Code == Forbidden {
+			*t = (*ForbiddenError)(e)
+			return true
+		}
+	case **NotFoundError:
+		if e.Code == NotFound {
+			*t = (*NotFoundError)(e)
+			return true
+		}
+	case **ConflictError:
+		if e.Code == Conflict {
+			*t = (*ConflictError)(e)
+			return true
+		}
+	case **AlreadyExistsError:
+		if e.Code == AlreadyExists {
+			*t = (*AlreadyExistsError)(e)
+			return true
+		}
+	case **TimeoutError:
+		if e.Code == Timeout {
+			*t = (*TimeoutError)(e)
+			return true
+		}
+	case **InternalError:
+		if e.Code == Internal {
+			*t = (*InternalError)(e)
+			return true
+		}
+	case **UnavailableError:
+		if e.Code == Unavailable {
+			*t = (*UnavailableError)(e)
+			return true
+		}
+	case **TooManyRequestsError:
+		if e.Code == TooManyRequests {
+			*t = (*TooManyRequestsError)(e)
+			return true
+		}
+	case **NotImplementedError:
+		if e.Code == NotImplemented {
+			*t = (*NotImplementedError)(e)
+			return true
+		}
+	case **CancelledError:
+		if e.Code == Cancelled {
+			*t = (*CancelledError)(e)
+			return true
+		}
+	case **PreconditionFailedError:
+		if e.Code == PreconditionFailed {
+			*t = (*PreconditionFailedError)(e)
+			return true
+		}
+	case **ResourceExhaustedError:
+		if e.Code == ResourceExhausted {
+			*t = (*ResourceExhaustedError)(e)
+			return true
+		}
+	case **GoneError:
+		if e.Code == Gone {
+			*t = (*GoneError)(e)
+			return true
+		}
+	case **PayloadTooLargeError:
+		if e.Code == PayloadTooLarge {
+			*t = (*PayloadTooLargeError)(e)
+			return true
+		}
+	case **UnsupportedMediaTypeError:
+		if e.Code == UnsupportedMediaType {
+			*t = (*UnsupportedMediaTypeError)(e)
+			return true
+		}
+	}
+	return false
+}
+
+// BadRequestError is an Error with the BadRequest code, for use with errors.As
+type BadRequestError Error
+
+// Error implements the error interface
+func (e *BadRequestError) Error() string {
+	return (*Error)(e).Error()
+}
+
+// Unwrap returns the wrapped error
+func (e *BadRequestError) Unwrap() error {
+	return e.Err
+}
+
+// Base returns the matched Error
+func (e *BadRequestError) Base() *Error {
+	return (*Error)(e)
+}
+
+// ValidationError is an Error with the Validation code, for use with errors.As
+type ValidationError Error
+
+// Error implements the error interface
+func (e *ValidationError) Error() string {
+	return (*Error)(e).Error()
+}
+
+// Unwrap returns the wrapped error
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// Base returns the matched Error
+func (e *ValidationError) Base() *Error {
+	return (*Error)(e)
+}
+
+// UnauthorizedError is an Error with the Unauthorized code, for use with errors.As
+type UnauthorizedError Error
+
+// Error implements the error interface
+func (e *UnauthorizedError) Error() string {
+	return (*Error)(e).Error()
+}
+
+// Unwrap returns the wrapped error
+func (e *UnauthorizedError) Unwrap() error {
+	return e.Err
+}
+
+// Base returns the matched Error
+func (e *UnauthorizedError) Base() *Error {
+	return (*Error)(e)
+}
+
+// ForbiddenError is an Error with the Forbidden code, for use with errors.As
+type ForbiddenError Error
+
+// Error implements the error interface
+func (e *ForbiddenError) Error() string {
+	return (*Error)(e).Error()
+}
+
+// Unwrap returns the wrapped error
+func (e *ForbiddenError) Unwrap() error {
+	return e.Err
+}
+
+// Base returns the matched Error
+func (e *ForbiddenError) Base() *Error {
+	return (*Error)(e)
+}
+
+// NotFoundError is an Error with the NotFound code, for use with errors.As
+type NotFoundError Error
+
+// Error implements the error interface
+func (e *NotFoundError) Error() string {
+	return (*Error)(e).Error()
+}
+
+// Unwrap returns the wrapped error
+func (e *NotFoundError) Unwrap() error {
+	return e.Err
+}
+
+// Base returns the matched Error
+func (e *NotFoundError) Base() *Error {
+	return (*Error)(e)
+}
+
+// ConflictError is an Error with the Conflict code, for use with errors.As
+type ConflictError Error
+
+// Error implements the error interface
+func (e *ConflictError) Error() string {
+	return (*Error)(e).Error()
+}
+
+// Unwrap returns the wrapped error
+func (e *ConflictError) Unwrap() error {
+	return e.Err
+}
+
+// Base returns the matched Error
+func (e *ConflictError) Base() *Error {
+	return (*Error)(e)
+}
+
+// AlreadyExistsError is an Error with the AlreadyExists code, for use with errors.As
+type AlreadyExistsError Error
+
+// Error implements the error interface
+func (e *AlreadyExistsError) Error() string {
+	return (*Error)(e).Error()
+}
+
+// Unwrap returns the wrapped error
+func (e *AlreadyExistsError) Unwrap() error {
+	return e.Err
+}
+
+// Base returns the matched Error
+func (e *AlreadyExistsError) Base() *Error {
+	return (*Error)(e)
+}
+
+// TimeoutError is an Error with the Timeout code, for use with errors.As
+type TimeoutError Error
+
+// Error implements the error interface
+func (e *TimeoutError) Error() string {
+	return (*Error)(e).Error()
+}
+
+// Unwrap returns the wrapped error
+func (e *TimeoutError) Unwrap() error {
+	return e.Err
+}
+
+// Base returns the matched Error
+func (e *TimeoutError) Base() *Error {
+	return (*Error)(e)
+}
+
+// InternalError is an Error with the Internal code, for use with errors.As
+type InternalError Error
+
+// Error implements the error interface
+func (e *InternalError) Error() string {
+	return (*Error)(e).Error()
+}
+
+// Unwrap returns the wrapped error
+func (e *InternalError) Unwrap() error {
+	return e.Err
+}
+
+// Base returns the matched Error
+func (e *InternalError) Base() *Error {
+	return (*Error)(e)
+}
+
+// UnavailableError is an Error with the Unavailable code, for use with errors.As
+type UnavailableError Error
+
+// Error implements the error interface
+func (e *UnavailableError) Error() string {
+	return (*Error)(e).Error()
+}
+
+// Unwrap returns the wrapped error
+func (e *UnavailableError) Unwrap() error {
+	return e.Err
+}
+
+// Base returns the matched Error
+func (e *UnavailableError) Base() *Error {
+	return (*Error)(e)
+}
+
+// TooManyRequestsError is an Error with the TooManyRequests code, for use with errors.As
+type TooManyRequestsError Error
+
+// Error implements the error interface
+func (e *TooManyRequestsError) Error() string {
+	return (*Error)(e).Error()
+}
+
+// Unwrap returns the wrapped error
+func (e *TooManyRequestsError) Unwrap() error {
+	return e.Err
+}
+
+// Base returns the matched Error
+func (e *TooManyRequestsError) Base() *Error {
+	return (*Error)(e)
+}
+
+// NotImplementedError is an Error with the NotImplemented code, for use with errors.As
+type NotImplementedError Error
+
+// Error implements the error interface
+func (e *NotImplementedError) Error() string {
+	return (*Error)(e).Error()
+}
+
+// Unwrap returns the wrapped error
+func (e *NotImplementedError) Unwrap() error {
+	return e.Err
+}
+
+// Base returns the matched Error
+func (e *NotImplementedError) Base() *Error {
+	return (*Error)(e)
+}
+
+// CancelledError is an Error with the Cancelled code, for use with errors.As
+type CancelledError Error
+
+// Error implements the error interface
+func (e *CancelledError) Error() string {
+	return (*Error)(e).Error()
+}
+
+// Unwrap returns the wrapped error
+func (e *CancelledError) Unwrap() error {
+	return e.Err
+}
+
+// Base returns the matched Error
+func (e *CancelledError) Base() *Error {
+	return (*Error)(e)
+}
+
+// PreconditionFailedError is an Error with the PreconditionFailed code, for use with errors.As
+type PreconditionFailedError Error
+
+// Error implements the error interface
+func (e *PreconditionFailedError) Error() string {
+	return (*Error)(e).Error()
+}
+
+// Unwrap returns the wrapped error
+func (e *PreconditionFailedError) Unwrap() error {
+	return e.Err
+}
+
+// Base returns the matched Error
+func (e *PreconditionFailedError) Base() *Error {
+	return (*Error)(e)
+}
+
+// ResourceExhaustedError is an Error with the ResourceExhausted code, for use with errors.As
+type ResourceExhaustedError Error
+
+// Error implements the error interface
+func (e *ResourceExhaustedError) Error() string {
+	return (*Error)(e).Error()
+}
+
+// Unwrap returns the wrapped error
+func (e *ResourceExhaustedError) Unwrap() error {
+	return e.Err
+}
+
+// Base returns the matched Error
+func (e *ResourceExhaustedError) Base() *Error {
+	return (*Error)(e)
+}
+
+// GoneError is an Error with the Gone code, for use with errors.As
+type GoneError Error
+
+// Error implements the error interface
+func (e *GoneError) Error() string {
+	return (*Error)(e).Error()
+}
+
+// Unwrap returns the wrapped error
+func (e *GoneError) Unwrap() error {
+	return e.Err
+}
+
+// Base returns the matched Error
+func (e *GoneError) Base() *Error {
+	return (*Error)(e)
+}
+
+// PayloadTooLargeError is an Error with the PayloadTooLarge code, for use with errors.As
+type PayloadTooLargeError Error
+
+// Error implements the error interface
+func (e *PayloadTooLargeError) Error() string {
+	return (*Error)(e).Error()
+}
+
+// Unwrap returns the wrapped error
+func (e *PayloadTooLargeError) Unwrap() error {
+	return e.Err
+}
+
+// Base returns the matched Error
+func (e *PayloadTooLargeError) Base() *Error {
+	return (*Error)(e)
+}
+
+// UnsupportedMediaTypeError is an Error with the UnsupportedMediaType code, for use with errors.As
+type UnsupportedMediaTypeError Error
+
+// Error implements the error interface
+func (e *UnsupportedMediaTypeError) Error() string {
+	return (*Error)(e).Error()
+}
+
+// Unwrap returns the wrapped error
+func (e *UnsupportedMediaTypeError) Unwrap() error {
+	return e.Err
+}
+
+// Base returns the matched Error
+func (e *UnsupportedMediaTypeError) Base() *Error {
+	return (*Error)(e)
+}
+
+// Resource returns the kind of resource recorded with WithResource
+func (e *NotFoundError) Resource() string {
+	kind, _ := e.Fields[ResourceField].(string)
+	return kind
+}
+
+// ResourceID returns the resource identifier recorded with WithResource
+func (e *NotFoundError) ResourceID() any {
+	return e.Fields[ResourceIDField]
+}
+
+// Resource returns the kind of resource recorded with WithResource
+func (e *GoneError) Resource() string {
+	kind, _ := e.Fields[ResourceField].(string)
+	return kind
+}
+
+// ResourceID returns the resource identifier recorded with WithResource
+func (e *GoneError) ResourceID() any {
+	return e.Fields[ResourceIDField]
+}
+
+// Resource returns the kind of resource recorded with WithResource
+func (e *ConflictError) Resource() string {
+	kind, _ := e.Fields[ResourceField].(string)
+	return kind
+}
+
+// ResourceID returns the resource identifier recorded with WithResource
+func (e *ConflictError) ResourceID() any {
+	return e.Fields[ResourceIDField]
+}
+
+// Resource returns the kind of resource recorded with WithResource
+func (e *AlreadyExistsError) Resource() string {
+	kind, _ := e.Fields[ResourceField].(string)
+	return kind
+}
+
+// ResourceID returns the resource identifier recorded with WithResource
+func (e *AlreadyExistsError) ResourceID() any {
+	return e.Fields[ResourceIDField]
+}
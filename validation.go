@@ -0,0 +1,85 @@
+package errx
+
+import "errors"
+
+// FieldError describes why a single input field is invalid
+type FieldError struct {
+	Field   string `json:"field"`           // Name or path of the invalid field
+	Rule    string `json:"rule,omitempty"`  // Validation rule that failed, e.g. "required"
+	Message string `json:"message"`         // User-friendly description of the problem
+	Value   any    `json:"value,omitempty"` // Rejected value (if safe to expose)
+}
+
+// Error implements the error interface
+func (f FieldError) Error() string {
+	if f.Field == "" {
+		return f.Message
+	}
+	return f.Field + ": " + f.Message
+}
+
+// ValidationErrors collects field errors before reporting them as a single
+// Validation error
+type ValidationErrors []FieldError
+
+// Add records a field error and returns the collector for chaining
+func (v *ValidationErrors) Add(field, rule, message string) *ValidationErrors {
+	*v = append(*v, FieldError{Field: field, Rule: rule, Message: message})
+	return v
+}
+
+// AddValue is like Add but also records the rejected value
+func (v *ValidationErrors) AddValue(field, rule, message string, value any) *ValidationErrors {
+	*v = append(*v, FieldError{Field: field, Rule: rule, Message: message, Value: value})
+	return v
+}
+
+// Check records a field error if ok is false
+func (v *ValidationErrors) Check(ok bool, field, rule, message string) *ValidationErrors {
+	if !ok {
+		v.Add(field, rule, message)
+	}
+	return v
+}
+
+// Err returns a Validation error carrying the collected field errors
+// Returns nil if nothing was collected
+func (v ValidationErrors) Err() error {
+	if len(v) == 0 {
+		return nil
+	}
+	return NewValidation().WithFieldErrors(v...).build(1)
+}
+
+// WithFieldError records why a single input field is invalid
+func (b *Builder) WithFieldError(field, rule, message string) *Builder {
+	b.fieldErrors = append(b.fieldErrors, FieldError{Field: field, Rule: rule, Message: message})
+	return b
+}
+
+// WithFieldErrors records several field errors at once
+func (b *Builder) WithFieldErrors(errs ...FieldError) *Builder {
+	b.fieldErrors = append(b.fieldErrors, errs...)
+	return b
+}
+
+// FieldErrors returns the field errors recorded on this error, excluding its causes
+func (e *Error) FieldErrors() []FieldError {
+	return e.fieldErrors
+}
+
+// FieldErrors returns the field errors recorded anywhere in the error chain,
+// outermost first
+// Returns nil if none were recorded
+func FieldErrors(err error) []FieldError {
+	var errs []FieldError
+	for cause := err; cause != nil; cause = errors.Unwrap(cause) {
+		switch e := cause.(type) {
+		case *Error:
+			errs = append(errs, e.fieldErrors...)
+		case *RemoteError:
+			errs = append(errs, e.FieldErrors...)
+		}
+	}
+	return errs
+}
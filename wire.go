@@ -0,0 +1,136 @@
+package errx
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// WireVersion is the version of the wire envelope produced by this package
+const WireVersion = 1
+
+// WireContentType is the media type of encoded wire envelopes
+const WireContentType = "application/vnd.errx+json"
+
+// Envelope is the versioned wire format used to pass errors between services
+type Envelope struct {
+	Version     int            `json:"version"`                // Wire format version
+	Code        Code           `json:"code"`                   // Error classification code
+	ID          string         `json:"id,omitempty"`           // Identifier of the occurrence
+	NumericCode int            `json:"numeric_code,omitempty"` // Stable numeric identifier of the code
+	Message     string         `json:"message"`                // User-friendly error message
+	Fields      map[string]any `json:"fields,omitempty"`       // Structured context
+	FieldErrors []FieldError   `json:"field_errors,omitempty"` // Per-field validation failures
+	Causes      []string       `json:"causes,omitempty"`       // Flattened cause chain, outermost first
+	Time        time.Time      `json:"time,omitzero"`          // Creation time of the original failure
+}
+
+// NewEnvelope builds the wire envelope for an error, applying the installed sanitizer
+// Fields are filtered by PublicFields and the causes of a recovered panic are
+// left out, so the panic value doesn't cross the wire
+func NewEnvelope(err error) Envelope {
+	env := Envelope{
+		Version:     WireVersion,
+		Code:        GetCode(err),
+		ID:          GetID(err),
+		NumericCode: NumericCode(err),
+		Message:     Sanitize(GetMessage(err)),
+		Fields:      PublicFields(GetFields(err)),
+		FieldErrors: SanitizeFieldErrors(FieldErrors(err)),
+		Time:        GetTime(err),
+	}
+
+	var e *Error
+	if !errors.As(err, &e) {
+		return env
+	}
+	for layer := e; layer.Err != nil && !recovered(layer); {
+		next, ok := layer.Err.(*Error)
+		if !ok {
+			env.Causes = append(env.Causes, Sanitize(layer.Err.Error()))
+			break
+		}
+		env.Causes = append(env.Causes, fmt.Sprintf("[%s] %s", next.Code, Sanitize(next.Message)))
+		layer = next
+	}
+	return env
+}
+
+// RemoteError returns the error described by the envelope
+func (env Envelope) RemoteError() *RemoteError {
+	return &RemoteError{
+		Code:        env.Code,
+		ID:          env.ID,
+		Message:     env.Message,
+		Fields:      env.Fields,
+		FieldErrors: env.FieldErrors,
+		Causes:      env.Causes,
+		Time:        env.Time,
+	}
+}
+
+// EncodeWire encodes an error as a JSON wire envelope
+func EncodeWire(err error) ([]byte, error) {
+	return json.Marshal(NewEnvelope(err))
+}
+
+// DecodeWire decodes a JSON wire envelope produced by EncodeWire
+// Returns an error if the data isn't an envelope or uses an unsupported version
+func DecodeWire(data []byte) (*RemoteError, error) {
+	var env Envelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		return nil, err
+	}
+	if env.Version < 1 || env.Version > WireVersion {
+		return nil, fmt.Errorf("errx: unsupported wire version %d", env.Version)
+	}
+	if env.Code == "" {
+		return nil, errors.New("errx: wire envelope has no code")
+	}
+	return env.RemoteError(), nil
+}
+
+// RemoteError is an error decoded from another service
+// It behaves like an Error for IsCode, GetCode, GetMessage and errors.As,
+// while the original cause chain is only available as a summary
+type RemoteError struct {
+	Code        Code           // Error classification code
+	ID          string         // Identifier of the occurrence (if any)
+	Message     string         // User-friendly error message
+	Fields      map[string]any // Structured context (if any)
+	FieldErrors []FieldError   // Per-field validation failures (if any)
+	Causes      []string       // Flattened cause chain, outermost first
+	Time        time.Time      // Creation time of the original failure (if recorded)
+}
+
+// Error implements the error interface using the same format as Error
+func (e *RemoteError) Error() string {
+	if len(e.Causes) > 0 {
+		return fmt.Sprintf("[%s] %s: %s", e.Code, e.Message, strings.Join(e.Causes, ": "))
+	}
+	return fmt.Sprintf("[%s] %s", e.Code, e.Message)
+}
+
+// As allows errors.As to treat a RemoteError as an Error or a typed error
+func (e *RemoteError) As(target any) bool {
+	base := &Error{
+		Code:        e.Code,
+		Message:     e.Message,
+		Fields:      e.Fields,
+		time:        e.Time,
+		id:          e.ID,
+		fieldErrors: e.FieldErrors,
+	}
+	if t, ok := target.(**Error); ok {
+		*t = base
+		return true
+	}
+	return base.As(target)
+}
+
+// Is reports whether target is an Error or RemoteError with the same code
+func (e *RemoteError) Is(target error) bool {
+	return IsCode(target, e.Code)
+}
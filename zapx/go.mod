@@ -0,0 +1,12 @@
+module github.com/nordew/go-errx/zapx
+
+go 1.24.1
+
+require (
+	github.com/nordew/go-errx v0.0.0
+	go.uber.org/zap v1.27.0
+)
+
+require go.uber.org/multierr v1.10.0 // indirect
+
+replace github.com/nordew/go-errx => ../
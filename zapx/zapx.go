@@ -0,0 +1,107 @@
+// Package zapx logs errx errors as structured zap fields
+package zapx
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/nordew/go-errx"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Field returns a zap field named "error" holding the structured error
+func Field(err error) zap.Field {
+	return NamedField("error", err)
+}
+
+// NamedField returns a zap field with the given key holding the structured error
+func NamedField(key string, err error) zap.Field {
+	if err == nil {
+		return zap.Skip()
+	}
+	return zap.Object(key, Object(err))
+}
+
+// Object returns a zapcore.ObjectMarshaler that encodes the code, message,
+// cause, fields and stack of an error with the installed sanitizer applied
+func Object(err error) zapcore.ObjectMarshaler {
+	return object{err: err}
+}
+
+type object struct {
+	err error
+}
+
+// MarshalLogObject implements zapcore.ObjectMarshaler
+func (o object) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	var e *errx.Error
+	if !errors.As(o.err, &e) {
+		enc.AddString("message", errx.Sanitize(o.err.Error()))
+		return nil
+	}
+
+	enc.AddString("code", string(e.Code))
+	enc.AddString("message", errx.Sanitize(e.Message))
+	if e.Err != nil {
+		enc.AddString("cause", errx.Sanitize(e.Err.Error()))
+	}
+	if len(e.Fields) > 0 {
+		if err := enc.AddObject("fields", fields(errx.SanitizeFields(e.Fields))); err != nil {
+			return err
+		}
+	}
+	if frames := e.StackTrace(); len(frames) > 0 {
+		stack := make(stackArray, 0, len(frames))
+		for _, frame := range frames {
+			stack = append(stack, fmt.Sprintf("%s %s:%d", frame.Function, frame.File, frame.Line))
+		}
+		if err := enc.AddArray("stack", stack); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type fields map[string]any
+
+// MarshalLogObject implements zapcore.ObjectMarshaler
+func (f fields) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	for _, key := range slices.Sorted(maps.Keys(f)) {
+		if err := enc.AddReflected(key, f[key]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type stackArray []string
+
+// MarshalLogArray implements zapcore.ArrayMarshaler
+func (s stackArray) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for _, frame := range s {
+		enc.AppendString(frame)
+	}
+	return nil
+}
+
+// Level returns the zap level matching an error's severity
+func Level(err error) zapcore.Level {
+	switch errx.GetSeverity(err) {
+	case errx.SeverityInfo:
+		return zapcore.InfoLevel
+	case errx.SeverityWarning:
+		return zapcore.WarnLevel
+	}
+	return zapcore.ErrorLevel
+}
+
+// Log logs an error at the level derived from its severity with its structured field
+func Log(logger *zap.Logger, err error) {
+	if err == nil {
+		return
+	}
+	logger.Log(Level(err), errx.Sanitize(errx.GetMessage(err)), Field(err))
+}